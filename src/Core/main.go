@@ -8,11 +8,46 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 )
 
 func main() {
 	VERSION := "9.20.47-stable"
 
+	// --runtime, --host and --context may appear anywhere; remove them so
+	// subcommands do not see them
+	globalFlags := map[string]string{"runtime": "", "host": "", "context": ""}
+	var args []string
+	for i := 0; i < len(os.Args); i++ {
+		matched := false
+		for name := range globalFlags {
+			if os.Args[i] == "--"+name && i+1 < len(os.Args) {
+				globalFlags[name] = os.Args[i+1]
+				i++
+				matched = true
+			} else if strings.HasPrefix(os.Args[i], "--"+name+"=") {
+				globalFlags[name] = strings.TrimPrefix(os.Args[i], "--"+name+"=")
+				matched = true
+			}
+		}
+		if !matched {
+			args = append(args, os.Args[i])
+		}
+	}
+	os.Args = args
+	if err := Docker.SetRuntime(globalFlags["runtime"]); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "host" {
+		// Only edits the config, so no engine is needed
+		base.HostCommand(os.Args[2:])
+	}
+	if err := Docker.UseEngine(globalFlags["host"], globalFlags["context"]); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
 	// handle version flag without requiring sudo
 	if len(os.Args) > 1 && os.Args[1] == "--version" {
 		fmt.Println("ContainDB CLI Version:", VERSION)
@@ -20,7 +55,7 @@ func main() {
 	} else if len(os.Args) > 1 && os.Args[1] == "--help" {
 		fmt.Println("ContainDB CLI - A tool for managing Docker databases")
 		if runtime.GOOS != "windows" {
-			fmt.Println("Usage: sudo containdb")
+			fmt.Println("Usage: containdb (as a member of the docker group, with rootless Docker or DOCKER_HOST; sudo also works)")
 		} else {
 			fmt.Println("Usage: containdb (run as Administrator)")
 		}
@@ -29,8 +64,22 @@ func main() {
 		fmt.Println("  --help             Show this help message")
 		fmt.Println("  --install-docker   Install Docker if not installed")
 		fmt.Println("  --uninstall-docker Uninstall Docker if installed")
-		fmt.Println("  --export   Export Docker Compose file with all running services")
-		fmt.Println("  --import ./docker-compose.yml      Import and run services from a Docker Compose file")
+		fmt.Println("  --export [--format compose|env|script|stack|k8s|helm] [--only a,b] [--exclude c] [--output path] [--backup] [--redact]   Export running services")
+		fmt.Println("  --import ./docker-compose.yml      Import and run services from a Docker Compose or ContainDB stack file")
+		fmt.Println("  --runtime docker|podman|nerdctl    Container runtime to use (default: auto-detected, or $CONTAINDB_RUNTIME)")
+		fmt.Println("  --host <name|url>   Use a saved host or a remote engine URL (tcp://, ssh://); DOCKER_HOST also works")
+		fmt.Println("  --context <name>    Use a Docker context (a Podman connection with --runtime podman)")
+		fmt.Println("Commands:")
+		fmt.Println("  install <database> [--file ./app.db] [--memory 2g] [--cpus 2] [--shm-size 256m] [--init ./sql/] [--app-user app --app-password pw --app-db app] [--no-auth] [--bind 127.0.0.1] [--tls]   Install a database or tool without the menu")
+		fmt.Println("  stop|start|restart|pause|unpause [container]   Change a container's state without removing it")
+		fmt.Println("  logs <container> [-f] [--since 10m] [--level error]   Show container logs, filtered by severity")
+		fmt.Println("  profile <container> on|off|tail [--threshold 100] [--all]   Record slow or all queries and follow them")
+		fmt.Println("  seed <container> <file|directory>...   Load SQL, JS, Redis command, search or Qdrant seed files")
+		fmt.Println("  user add|list|remove <container> [username] [--password pw] [--database db] [--read-only]   Manage application users")
+		fmt.Println("  gateway up [--port 443] [--bind 127.0.0.1] | down | status   Serve management tools at https://<tool>.containdb.localhost")
+		fmt.Println("  tls ca | export <path> | url <container>   Show or export the local CA, print a TLS connection string")
+		fmt.Println("  host add <name> <url> [--tls-verify] [--cert-path dir] | list | remove <name> | use <name|local>   Manage saved remote hosts")
+		fmt.Println("  doctor [--json]   Diagnose Docker, permissions, network, volumes and crashing containers")
 		os.Exit(0) // Exit after handling flags
 	} else if len(os.Args) > 1 && os.Args[1] == "--install-docker" {
 		if !Docker.IsDockerInstalled() {
@@ -44,6 +93,9 @@ func main() {
 			fmt.Println("Docker is already installed.")
 		}
 		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		// Diagnoses a missing or broken Docker, so it runs before the Docker checks below
+		base.DoctorCommand(os.Args[2:])
 	}
 
 	// Replace Ctrl+C handler to avoid triggering on normal exit
@@ -62,13 +114,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Check for admin/root privileges (only on Linux, optional on Windows/macOS for Docker)
-	if runtime.GOOS == "linux" {
-		if !Docker.IsAdmin() {
-			fmt.Println("❌ Please run this program with sudo (Linux requires root for Docker)")
-			os.Exit(1)
-		}
-	} else if runtime.GOOS == "windows" {
+	// Root is not required on Linux: the docker group, rootless Docker or DOCKER_HOST
+	// give access to the daemon, and only installing Docker asks for sudo
+	if runtime.GOOS == "windows" {
 		if !Docker.IsAdmin() {
 			fmt.Println("⚠️  Warning: Not running as Administrator. Docker may require admin privileges.")
 			fmt.Println("   Continuing anyway...")
@@ -78,15 +126,26 @@ func main() {
 	// Check if Docker is installed and if not, prompt to install it
 	base.DockerStarter()
 
-	// Handle command line flags with FlagHandler function
-	base.FlagHandler()
+	if err := Docker.ResolveDockerAccess(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("   Run `containDB doctor` for a full diagnosis.")
+		os.Exit(1)
+	}
+
+	if Docker.IsRemoteEngine() {
+		fmt.Println("🌐 Using the remote engine at", Docker.EngineHost())
+	}
 
+	// Commands started from flags (install, import) need the network as well
 	errs := Docker.CreateDockerNetworkIfNotExists()
 	if errs != nil {
 		fmt.Println("Failed to create Docker network:", errs)
 		return
 	}
 
+	// Handle command line flags with FlagHandler function
+	base.FlagHandler()
+
 	// Show welcome banner
 	base.ShowBanner()
 
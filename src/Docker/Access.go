@@ -0,0 +1,72 @@
+package Docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveDockerAccess makes sure the current user can talk to a Docker daemon
+// without root: through DOCKER_HOST, the active docker context, the docker
+// group, or a rootless daemon. When only the rootless socket answers,
+// DOCKER_HOST is set so every docker command ContainDB runs uses it.
+func ResolveDockerAccess() error {
+	output, err := dockerPing()
+	if err == nil {
+		return nil
+	}
+	if RuntimeName() != "docker" {
+		// Podman needs no daemon and nerdctl talks to containerd directly
+		return fmt.Errorf("%s is not usable: %s", RuntimeName(), firstLine(output))
+	}
+
+	if IsLinux() && os.Getenv("DOCKER_HOST") == "" {
+		if socket := rootlessSocketPath(); socket != "" {
+			os.Setenv("DOCKER_HOST", "unix://"+socket)
+			if _, rootlessErr := dockerPing(); rootlessErr == nil {
+				fmt.Println("Using rootless Docker at", socket)
+				return nil
+			}
+			os.Unsetenv("DOCKER_HOST")
+		}
+	}
+
+	switch {
+	case strings.Contains(output, "permission denied"):
+		user := os.Getenv("USER")
+		if inDockerGroup(user) {
+			return fmt.Errorf("permission denied on the Docker socket. You were added to the docker group after this session started; log out and back in")
+		}
+		return fmt.Errorf("permission denied on the Docker socket. Run `sudo usermod -aG docker %s`, then log out and back in (or run containDB with sudo)", user)
+	case strings.Contains(output, "Cannot connect") || strings.Contains(output, "Is the docker daemon running"):
+		if host := os.Getenv("DOCKER_HOST"); host != "" {
+			return fmt.Errorf("cannot reach the Docker daemon at DOCKER_HOST=%s", host)
+		}
+		if IsLinux() {
+			return fmt.Errorf("the Docker daemon is not running. Start it with `sudo systemctl start docker` (or `systemctl --user start docker` for rootless Docker)")
+		}
+		return fmt.Errorf("the Docker daemon is not running. Start Docker Desktop and try again")
+	}
+	return fmt.Errorf("cannot reach the Docker daemon: %s", firstLine(output))
+}
+
+// dockerPing asks the runtime for the engine version and returns the CLI output
+func dockerPing() (string, error) {
+	out, err := Command(versionArgs()...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// rootlessSocketPath returns the socket of a rootless Docker daemon of the
+// current user, or "" when there is none
+func rootlessSocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	socket := filepath.Join(runtimeDir, "docker.sock")
+	if info, err := os.Stat(socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		return socket
+	}
+	return ""
+}
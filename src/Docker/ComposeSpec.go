@@ -0,0 +1,269 @@
+package Docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComposeFile is the part of the Compose specification ContainDB reads and writes
+type ComposeFile struct {
+	Services map[string]ComposeService `yaml:"services"`
+	Volumes  map[string]ComposeVolume  `yaml:"volumes,omitempty"`
+	Networks map[string]ComposeNetwork `yaml:"networks,omitempty"`
+}
+
+// ComposeService is one service of a ComposeFile. Fields that the spec allows
+// in several forms (list or map environment, short or long ports, ...) are
+// read in any form and written in one.
+type ComposeService struct {
+	Image         string                   `yaml:"image,omitempty"`
+	Build         interface{}              `yaml:"build,omitempty"`
+	ContainerName string                   `yaml:"container_name,omitempty"`
+	Entrypoint    ComposeStringList        `yaml:"entrypoint,omitempty"`
+	Command       ComposeStringList        `yaml:"command,omitempty"`
+	Restart       string                   `yaml:"restart,omitempty"`
+	Ports         ComposePorts             `yaml:"ports,omitempty"`
+	Environment   ComposeMapping           `yaml:"environment,omitempty"`
+	Volumes       ComposeVolumes           `yaml:"volumes,omitempty"`
+	Networks      ComposeNameList          `yaml:"networks,omitempty"`
+	NetworkMode   string                   `yaml:"network_mode,omitempty"`
+	DependsOn     ComposeNameList          `yaml:"depends_on,omitempty"`
+	Healthcheck   *ComposeHealthcheck      `yaml:"healthcheck,omitempty"`
+	Labels        ComposeMapping           `yaml:"labels,omitempty"`
+	MemLimit      string                   `yaml:"mem_limit,omitempty"`
+	CPUs          string                   `yaml:"cpus,omitempty"`
+	ShmSize       string                   `yaml:"shm_size,omitempty"`
+	Ulimits       map[string]ComposeUlimit `yaml:"ulimits,omitempty"`
+}
+
+// ComposeHealthcheck is the healthcheck section of a service
+type ComposeHealthcheck struct {
+	Test        ComposeStringList `yaml:"test"`
+	Interval    string            `yaml:"interval,omitempty"`
+	Timeout     string            `yaml:"timeout,omitempty"`
+	StartPeriod string            `yaml:"start_period,omitempty"`
+	Retries     int               `yaml:"retries,omitempty"`
+}
+
+// ComposeUlimit is a soft/hard limit pair of the ulimits section
+type ComposeUlimit struct {
+	Soft int64 `yaml:"soft"`
+	Hard int64 `yaml:"hard"`
+}
+
+// UnmarshalYAML accepts the single-value form (`nofile: 65535`) as well
+func (u *ComposeUlimit) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single int64
+	if err := unmarshal(&single); err == nil {
+		u.Soft, u.Hard = single, single
+		return nil
+	}
+	type plain ComposeUlimit
+	return unmarshal((*plain)(u))
+}
+
+// ComposeVolume is a top-level volume; Name keeps Compose from prefixing it with the project name
+type ComposeVolume struct {
+	Name     string         `yaml:"name,omitempty"`
+	External bool           `yaml:"external,omitempty"`
+	Labels   ComposeMapping `yaml:"labels,omitempty"`
+}
+
+// ComposeNetwork is a top-level network
+type ComposeNetwork struct {
+	Name     string `yaml:"name,omitempty"`
+	External bool   `yaml:"external,omitempty"`
+}
+
+// ComposeMapping is an environment or labels section, written as a map and
+// read from a map or a list of KEY=VALUE entries
+type ComposeMapping map[string]string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (m *ComposeMapping) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	*m = make(ComposeMapping)
+	var entries []string
+	if err := unmarshal(&entries); err == nil {
+		for _, entry := range entries {
+			// A bare KEY takes its value from the shell running compose
+			key, value, _ := strings.Cut(entry, "=")
+			(*m)[key] = value
+		}
+		return nil
+	}
+	var values map[string]interface{}
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+	for key, value := range values {
+		if value == nil {
+			(*m)[key] = ""
+		} else {
+			(*m)[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+// ComposeStringList is a command or entrypoint, written as a list and read
+// from a list or a string
+type ComposeStringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (l *ComposeStringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var line string
+	if err := unmarshal(&line); err == nil {
+		*l = strings.Fields(line)
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// ComposeNameList is a networks or depends_on section, written as a list and
+// read from a list or a map keyed by name
+type ComposeNameList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (l *ComposeNameList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*l = list
+		return nil
+	}
+	var byName map[string]interface{}
+	if err := unmarshal(&byName); err != nil {
+		return err
+	}
+	*l = nil
+	for name := range byName {
+		*l = append(*l, name)
+	}
+	sort.Strings(*l)
+	return nil
+}
+
+// ComposePorts is a ports section, written in the short syntax and read from
+// the short syntax, bare port numbers or the long syntax
+type ComposePorts []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (p *ComposePorts) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items []interface{}
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+	*p = nil
+	for _, item := range items {
+		switch value := item.(type) {
+		case map[interface{}]interface{}:
+			port := PortMapping{
+				HostIP:        stringField(value, "host_ip"),
+				HostPort:      stringField(value, "published"),
+				ContainerPort: stringField(value, "target"),
+				Protocol:      stringField(value, "protocol"),
+			}
+			if port.ContainerPort == "" {
+				return fmt.Errorf("port %v has no target", value)
+			}
+			*p = append(*p, port.String())
+		default:
+			*p = append(*p, fmt.Sprint(value))
+		}
+	}
+	return nil
+}
+
+// ComposeVolumes is a service's volumes section, written in the short syntax
+// and read from the short or the long syntax
+type ComposeVolumes []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (v *ComposeVolumes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items []interface{}
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+	*v = nil
+	for _, item := range items {
+		switch value := item.(type) {
+		case map[interface{}]interface{}:
+			mount := stringField(value, "target")
+			if mount == "" {
+				return fmt.Errorf("volume %v has no target", value)
+			}
+			if source := stringField(value, "source"); source != "" {
+				mount = source + ":" + mount
+			}
+			if stringField(value, "read_only") == "true" {
+				mount += ":ro"
+			}
+			*v = append(*v, mount)
+		default:
+			*v = append(*v, fmt.Sprint(value))
+		}
+	}
+	return nil
+}
+
+// NamedVolume returns the volume of a short-syntax mount, or "" for bind
+// mounts and anonymous volumes
+func NamedVolume(mount string) string {
+	source, _, found := strings.Cut(mount, ":")
+	if !found || source == "" || strings.ContainsAny(source[:1], "./~$") {
+		return ""
+	}
+	return source
+}
+
+func stringField(values map[interface{}]interface{}, key string) string {
+	if value, ok := values[key]; ok && value != nil {
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// ParseComposePort parses a short-syntax port such as "8080:80",
+// "127.0.0.1:8080:80/udp", "[::1]:8080:80" or "80"
+func ParseComposePort(mapping string) (PortMapping, error) {
+	var port PortMapping
+	mapping, port.Protocol, _ = strings.Cut(mapping, "/")
+	if port.Protocol == "" {
+		port.Protocol = "tcp"
+	}
+	if strings.HasPrefix(mapping, "[") {
+		end := strings.Index(mapping, "]:")
+		if end < 0 {
+			return port, fmt.Errorf("invalid port '%s'", mapping)
+		}
+		port.HostIP = mapping[1:end]
+		mapping = mapping[end+2:]
+		parts := strings.Split(mapping, ":")
+		if len(parts) != 2 {
+			return port, fmt.Errorf("invalid port '%s'", mapping)
+		}
+		port.HostPort, port.ContainerPort = parts[0], parts[1]
+		return port, nil
+	}
+
+	parts := strings.Split(mapping, ":")
+	switch len(parts) {
+	case 1:
+		port.ContainerPort = parts[0]
+	case 2:
+		port.HostPort, port.ContainerPort = parts[0], parts[1]
+	case 3:
+		port.HostIP, port.HostPort, port.ContainerPort = parts[0], parts[1], parts[2]
+	default:
+		return port, fmt.Errorf("invalid port '%s'", mapping)
+	}
+	if port.ContainerPort == "" {
+		return port, fmt.Errorf("invalid port '%s'", mapping)
+	}
+	return port, nil
+}
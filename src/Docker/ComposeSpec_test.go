@@ -0,0 +1,137 @@
+package Docker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TestComposeRoundTrip exports containers the way `export` does and reads the
+// result back with the import parser; every setting must survive unchanged.
+func TestComposeRoundTrip(t *testing.T) {
+	infos := []ContainerInfo{
+		{
+			Name:  "redis-container",
+			Image: "redis:7",
+			Ports: []PortMapping{
+				{HostIP: "127.0.0.1", HostPort: "6379", ContainerPort: "6379", Protocol: "tcp"},
+				{HostIP: "::1", HostPort: "6380", ContainerPort: "6379", Protocol: "tcp"},
+				{HostPort: "5353", ContainerPort: "53", Protocol: "udp"},
+				{ContainerPort: "8001", Protocol: "tcp"},
+			},
+			Volumes: []VolumeMount{
+				{Volume: "redis-data", Destination: "/data"},
+				{Source: "./conf", Destination: "/usr/local/etc/redis", ReadOnly: true},
+			},
+			EnvVars:       []string{"REDIS_PASSWORD=pa$$word", "EMPTY="},
+			Networks:      []string{"ContainDB-Network"},
+			RestartPolicy: "unless-stopped",
+			Entrypoint:    []string{"docker-entrypoint.sh"},
+			Command:       []string{"sh", "-c", `redis-server --requirepass "$REDIS_PASSWORD" --save ''`},
+			Labels:        map[string]string{"com.containdb.engine": "redis", "note": "a: b # c"},
+			Healthcheck: &Healthcheck{
+				Test:        []string{"CMD-SHELL", "redis-cli -a \"$REDIS_PASSWORD\" ping | grep PONG"},
+				Interval:    10 * time.Second,
+				Timeout:     3 * time.Second,
+				StartPeriod: 90 * time.Second,
+				Retries:     5,
+			},
+			MemoryBytes:  512 << 20,
+			NanoCPUs:     1500000000,
+			ShmSizeBytes: 64 << 20,
+			Ulimits:      []Ulimit{{Name: "nofile", Soft: 65535, Hard: 65535}, {Name: "memlock", Soft: -1, Hard: -1}},
+		},
+		{
+			Name:          "postgresql-container",
+			Image:         "postgres:16",
+			Ports:         []PortMapping{{HostIP: "0.0.0.0", HostPort: "5432", ContainerPort: "5432", Protocol: "tcp"}},
+			RestartPolicy: "no",
+			Command:       []string{"postgres", "-c", "ssl=on"},
+		},
+	}
+
+	exported := buildComposeFile(infos)
+	data, err := yaml.Marshal(exported)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var imported ComposeFile
+	if err := yaml.Unmarshal(data, &imported); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+	if err := validateCompose(imported); err != nil {
+		t.Fatalf("validate: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(imported, exported) {
+		t.Fatalf("compose file does not read back as exported\nexported: %#v\nimported: %#v\n%s", exported, imported, data)
+	}
+
+	// The ports parse back to the bindings of the containers; 0.0.0.0 is the default
+	for _, info := range infos {
+		service := imported.Services[composeServiceName(info.Name)]
+		if len(service.Ports) != len(info.Ports) {
+			t.Fatalf("%s: got %d ports, want %d", info.Name, len(service.Ports), len(info.Ports))
+		}
+		for i, entry := range service.Ports {
+			got, err := ParseComposePort(entry)
+			if err != nil {
+				t.Fatalf("%s: %v", info.Name, err)
+			}
+			want := info.Ports[i]
+			if want.HostIP == "0.0.0.0" {
+				want.HostIP = ""
+			}
+			if got != want {
+				t.Errorf("%s: port %q parsed as %#v, want %#v", info.Name, entry, got, want)
+			}
+		}
+	}
+
+	// The import runs the document rewritten with ContainDB's label, network and free ports
+	var document yamlMap
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		t.Fatalf("unmarshal document: %v", err)
+	}
+	redis := composeServiceName(infos[0].Name)
+	plan := importPlan{Remaps: map[string]string{redis + "/6379": "16379"}, Unmanaged: map[string]string{}}
+	rewriteComposeDocument(document, plan)
+	rewritten, err := yaml.Marshal(document)
+	if err != nil {
+		t.Fatalf("marshal rewritten: %v", err)
+	}
+	var run ComposeFile
+	if err := yaml.Unmarshal(rewritten, &run); err != nil {
+		t.Fatalf("unmarshal rewritten: %v\n%s", err, rewritten)
+	}
+	if err := validateCompose(run); err != nil {
+		t.Fatalf("validate rewritten: %v\n%s", err, rewritten)
+	}
+
+	want := ComposeFile{Services: map[string]ComposeService{}, Volumes: map[string]ComposeVolume{}, Networks: map[string]ComposeNetwork{}}
+	for name, network := range exported.Networks {
+		want.Networks[name] = network
+	}
+	want.Networks["ContainDB-Network"] = ComposeNetwork{External: true}
+	for name, service := range exported.Services {
+		labels := ComposeMapping{ManagedLabel: "true"}
+		for key, value := range service.Labels {
+			labels[key] = value
+		}
+		service.Labels = labels
+		if len(service.Networks) == 0 {
+			service.Networks = ComposeNameList{"default", "ContainDB-Network"}
+		}
+		service.Ports = append(ComposePorts(nil), service.Ports...)
+		want.Services[name] = service
+	}
+	want.Services[redis].Ports[0] = "127.0.0.1:16379:6379"
+	for name, volume := range exported.Volumes {
+		volume.Labels = ComposeMapping{ManagedLabel: "true"}
+		want.Volumes[name] = volume
+	}
+	if !reflect.DeepEqual(run, want) {
+		t.Fatalf("rewritten compose file differs\nwant: %#v\ngot:  %#v\n%s", want, run, rewritten)
+	}
+}
@@ -0,0 +1,70 @@
+package Docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SavedHost is a remote container engine saved with `containdb host add`
+type SavedHost struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"` // DOCKER_HOST value, e.g. ssh://deploy@staging-box or tcp://10.0.0.5:2376
+	TLSVerify bool   `json:"tls_verify,omitempty"`
+	CertPath  string `json:"cert_path,omitempty"` // directory with ca.pem, cert.pem and key.pem
+}
+
+// Config is the ContainDB configuration file of the current user
+type Config struct {
+	Hosts       []SavedHost `json:"hosts"`
+	DefaultHost string      `json:"default_host,omitempty"` // saved host used when --host is not given
+}
+
+// ConfigPath returns the location of the ContainDB configuration file
+func ConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = GetTempDir()
+	}
+	return filepath.Join(dir, "containdb", "config.json")
+}
+
+// LoadConfig reads the configuration file; a missing file is an empty config
+func LoadConfig() (Config, error) {
+	var config Config
+	data, err := os.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid config file %s: %v", ConfigPath(), err)
+	}
+	return config, nil
+}
+
+// SaveConfig writes the configuration file, readable by the current user only
+func SaveConfig(config Config) error {
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// FindHost returns the saved host with the given name
+func (c Config) FindHost(name string) (SavedHost, bool) {
+	for _, host := range c.Hosts {
+		if host.Name == name {
+			return host, true
+		}
+	}
+	return SavedHost{}, false
+}
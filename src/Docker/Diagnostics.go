@@ -0,0 +1,276 @@
+package Docker
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// restartSampleWindow is how long CheckRestartLoops watches the restart counts;
+// the counts are lifetime totals, so only growth means a container crashes now
+const restartSampleWindow = 5 * time.Second
+
+// CheckDockerDaemon verifies that the Docker daemon answers and reports its
+// version. The second return value is false when the daemon is unreachable,
+// so checks that talk to it can be skipped.
+func CheckDockerDaemon() (CheckResult, bool) {
+	result := CheckResult{Name: "Container engine"}
+	out, err := Command(versionArgs()...).CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		result.Status = CheckFail
+		switch {
+		case strings.Contains(output, "permission denied"):
+			result.Message = "permission denied on the Docker socket"
+			result.Remediation = "Add your user to the docker group (see the socket check below) or run with sudo"
+		case strings.Contains(output, "Cannot connect") || strings.Contains(output, "Is the docker daemon running"):
+			result.Message = "the Docker daemon is not running"
+			result.Remediation = "Start it with `sudo systemctl start docker`, or start Docker Desktop"
+			if !IsLinux() {
+				result.Remediation = "Start Docker Desktop and wait until it reports it is running"
+			}
+		default:
+			result.Message = fmt.Sprintf("docker version failed: %s", firstLine(output))
+			result.Remediation = "Install Docker with `containDB --install-docker`"
+		}
+		return result, false
+	}
+
+	result.Status, result.Message = CheckPass, fmt.Sprintf("%s %s", RuntimeName(), output)
+	if major, err := strconv.Atoi(strings.Split(output, ".")[0]); err == nil && major < 20 && RuntimeName() == "docker" {
+		result.Status = CheckWarn
+		result.Remediation = "Docker Engine 20.10 or newer is recommended, please upgrade"
+	}
+	return result, true
+}
+
+// CheckDockerSocket verifies that the current user can use Docker without sudo
+// through the docker group or rootless Docker, or reports the remote daemon in use
+func CheckDockerSocket() CheckResult {
+	result := CheckResult{Name: "Docker access"}
+	if IsRemoteEngine() {
+		result.Status, result.Message = CheckPass, "remote engine at "+EngineHost()
+		return result
+	}
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		result.Status, result.Message = CheckPass, "using DOCKER_HOST="+host
+		return result
+	}
+	if !IsLinux() {
+		result.Status, result.Message = CheckPass, "managed by Docker Desktop"
+		return result
+	}
+
+	user := os.Getenv("USER")
+	if os.Geteuid() == 0 {
+		user = os.Getenv("SUDO_USER")
+		if user == "" {
+			result.Status, result.Message = CheckPass, "running as root"
+			return result
+		}
+		if inDockerGroup(user) {
+			result.Status, result.Message = CheckPass, fmt.Sprintf("%s is in the docker group, sudo is not needed", user)
+			return result
+		}
+		result.Status = CheckWarn
+		result.Message = fmt.Sprintf("%s is not in the docker group, so Docker needs sudo", user)
+		result.Remediation = fmt.Sprintf("Run `sudo usermod -aG docker %s`, then log out and back in", user)
+		return result
+	}
+
+	if socket := rootlessSocketPath(); socket != "" {
+		result.Status, result.Message = CheckPass, "rootless Docker at "+socket
+		return result
+	}
+
+	conn, err := net.Dial("unix", "/var/run/docker.sock")
+	if err == nil {
+		conn.Close()
+		result.Status, result.Message = CheckPass, "/var/run/docker.sock is accessible"
+		return result
+	}
+
+	result.Status = CheckFail
+	result.Message = fmt.Sprintf("cannot open /var/run/docker.sock: %v", err)
+	if inDockerGroup(user) {
+		result.Remediation = "You are in the docker group but this session predates it; log out and back in"
+	} else {
+		result.Remediation = fmt.Sprintf("Run `sudo usermod -aG docker %s`, then log out and back in", user)
+	}
+	return result
+}
+
+func inDockerGroup(user string) bool {
+	out, err := exec.Command("id", "-nG", user).Output()
+	if err != nil {
+		return false
+	}
+	for _, group := range strings.Fields(string(out)) {
+		if group == "docker" {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckComposePlugin verifies that Compose is available for the selected
+// runtime to run exported compose files
+func CheckComposePlugin() CheckResult {
+	result := CheckResult{Name: "Compose"}
+	if out, err := ComposeCommand("version").Output(); err == nil {
+		result.Status, result.Message = CheckPass, firstLine(strings.TrimSpace(string(out)))
+		return result
+	}
+	result.Status = CheckWarn
+	result.Message = fmt.Sprintf("not installed, exported compose files cannot be started with `%s compose up`", RuntimeName())
+	switch RuntimeName() {
+	case "podman":
+		result.Remediation = "Install podman-compose (or docker-compose, which `podman compose` uses as well)"
+	case "nerdctl":
+		result.Remediation = "Update nerdctl, compose is built into nerdctl 0.11 and newer"
+	default:
+		result.Remediation = "Install the docker-compose-plugin package, or update Docker Desktop"
+	}
+	return result
+}
+
+// CheckContainDBNetwork verifies that ContainDB-Network exists as a bridge network
+func CheckContainDBNetwork() CheckResult {
+	result := CheckResult{Name: "ContainDB-Network"}
+	out, err := Command("network", "inspect", "ContainDB-Network", "--format", "{{.Driver}}").Output()
+	if err != nil {
+		result.Status = CheckWarn
+		result.Message = "does not exist"
+		result.Remediation = "It is created the next time you run containDB"
+		return result
+	}
+	driver := strings.TrimSpace(string(out))
+	if driver != "bridge" {
+		result.Status = CheckWarn
+		result.Message = fmt.Sprintf("uses the %s driver instead of bridge", driver)
+		result.Remediation = "Remove all ContainDB containers, then `docker network rm ContainDB-Network` and run containDB again"
+		return result
+	}
+	result.Status, result.Message = CheckPass, "exists (bridge driver)"
+	return result
+}
+
+// CheckOrphanedVolumes reports ContainDB data volumes no container uses anymore
+func CheckOrphanedVolumes() CheckResult {
+	result := CheckResult{Name: "Volumes"}
+	volumes, err := ListContainDBVolumes()
+	if err != nil {
+		result.Status, result.Message = CheckWarn, err.Error()
+		return result
+	}
+
+	for _, volume := range volumes {
+		if inUse, _, err := IsVolumeInUse(volume); err == nil && !inUse {
+			result.Details = append(result.Details, volume)
+		}
+	}
+	if len(result.Details) == 0 {
+		result.Status, result.Message = CheckPass, fmt.Sprintf("%d ContainDB volumes, all in use", len(volumes))
+		return result
+	}
+	result.Status = CheckWarn
+	result.Message = fmt.Sprintf("%d volumes are not used by any container", len(result.Details))
+	result.Remediation = "Reuse them by installing the database again, or delete them with \"Remove Volume\" in the menu"
+	return result
+}
+
+// CheckRestartLoops reports ContainDB containers that keep crashing, with
+// their last log lines: those restarting, or restarted while being watched
+func CheckRestartLoops() []CheckResult {
+	instances, err := ListContainDBInstances()
+	if err != nil {
+		return []CheckResult{{Name: "Containers", Status: CheckWarn, Message: err.Error()}}
+	}
+
+	before := make(map[string]int)
+	for _, instance := range instances {
+		before[instance.Name] = restartCount(instance.Name)
+	}
+	if len(instances) > 0 {
+		time.Sleep(restartSampleWindow)
+	}
+
+	var results []CheckResult
+	for _, instance := range instances {
+		restarts := restartCount(instance.Name)
+		if instance.State != "restarting" && restarts == before[instance.Name] {
+			continue
+		}
+
+		logs, _ := Command("logs", "--tail", "5", instance.Name).CombinedOutput()
+		results = append(results, CheckResult{
+			Name:        "Container " + instance.Name,
+			Status:      CheckFail,
+			Message:     fmt.Sprintf("is %s and restarted %d times", instance.State, restarts),
+			Remediation: fmt.Sprintf("Inspect the full output with `containDB logs %s --level error`", instance.Name),
+			Details:     strings.Split(strings.TrimSpace(string(logs)), "\n"),
+		})
+	}
+	if len(results) == 0 {
+		results = append(results, CheckResult{Name: "Containers", Status: CheckPass,
+			Message: fmt.Sprintf("%d ContainDB containers, none in a restart loop", len(instances))})
+	}
+	return results
+}
+
+// restartCount returns how often the engine restarted a container since it was created
+func restartCount(name string) int {
+	out, _ := Command("inspect", "--format", "{{.RestartCount}}", name).Output()
+	count, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	return count
+}
+
+// CheckPortConflicts reports stopped ContainDB containers that cannot start
+// again because another process took one of their host ports
+func CheckPortConflicts() []CheckResult {
+	instances, err := ListContainDBInstances()
+	if err != nil {
+		return nil
+	}
+
+	var results []CheckResult
+	for _, instance := range instances {
+		if instance.State != "exited" && instance.State != "created" {
+			continue
+		}
+		out, _ := Command("inspect", "--format",
+			"{{range $p, $b := .HostConfig.PortBindings}}{{range $b}}{{.HostPort}} {{end}}{{end}}", instance.Name).Output()
+		for _, port := range strings.Fields(string(out)) {
+			if check := CheckPort(port); check.Status == CheckFail {
+				check.Name = "Container " + instance.Name
+				check.Message = fmt.Sprintf("cannot start, host port %s %s", port, check.Message)
+				results = append(results, check)
+			}
+		}
+	}
+	if len(results) == 0 {
+		results = append(results, CheckResult{Name: "Port conflicts", Status: CheckPass, Message: "none"})
+	}
+	return results
+}
+
+// CheckDockerDiskUsage reports the space used by images, containers and volumes
+func CheckDockerDiskUsage() CheckResult {
+	result := CheckResult{Name: "Docker disk usage"}
+	out, err := Command("system", "df", "--format", "{{.Type}}: {{.Size}} ({{.Reclaimable}} reclaimable)").Output()
+	if err != nil {
+		result.Status, result.Message = CheckWarn, "could not read docker system df"
+		return result
+	}
+	result.Status, result.Message = CheckPass, "space used by Docker"
+	result.Details = strings.Split(strings.TrimSpace(string(out)), "\n")
+	return result
+}
+
+func firstLine(text string) string {
+	return strings.SplitN(text, "\n", 2)[0]
+}
@@ -1,357 +1,447 @@
 package Docker
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
-	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v2"
 )
 
-// ContainerInfo holds details about a Docker container
+// ContainerInfo holds details about a Docker container, limited to the
+// settings that differ from its image's defaults
 type ContainerInfo struct {
 	Name          string
 	Image         string
-	Ports         []string
-	Volumes       []string
+	Ports         []PortMapping
+	ExposedPorts  []string // container ports the image or container exposes, e.g. "5432/tcp"
+	Volumes       []VolumeMount
 	EnvVars       []string
 	Networks      []string
-	Dependencies  []string
 	RestartPolicy string
-	Command       string
+	Entrypoint    []string
+	Command       []string
+	Labels        map[string]string
+	Healthcheck   *Healthcheck
+	MemoryBytes   int64
+	NanoCPUs      int64
+	ShmSizeBytes  int64
+	Ulimits       []Ulimit
 }
 
-// MakeDockerComposeWithAllServices creates a Docker Compose file from running containers
-func MakeDockerComposeWithAllServices() string {
-	fmt.Println("Generating Docker Compose file from running containers...")
+// PortMapping is a published port; HostIP is empty when bound to all interfaces
+type PortMapping struct {
+	HostIP        string
+	HostPort      string
+	ContainerPort string
+	Protocol      string // tcp or udp
+}
 
-	// Get all running containers on ContainDB network
-	containers, err := ListRunningDatabases()
-	if err != nil {
-		fmt.Printf("Error listing containers: %v\n", err)
-		return ""
-	}
+// VolumeMount is a named volume (Volume set) or a bind mount (Source set)
+type VolumeMount struct {
+	Volume      string
+	Source      string
+	Destination string
+	ReadOnly    bool
+}
 
-	if len(containers) == 0 {
-		fmt.Println("No containers found running on ContainDB-Network")
-		return ""
-	}
+// Healthcheck is a container health check; durations are in nanoseconds as Docker reports them
+type Healthcheck struct {
+	Test        []string
+	Interval    time.Duration
+	Timeout     time.Duration
+	StartPeriod time.Duration
+	Retries     int
+}
 
-	// Map to store container info
-	containerInfoMap := make(map[string]ContainerInfo)
+// Ulimit is a resource limit of the container's processes
+type Ulimit struct {
+	Name string
+	Soft int64
+	Hard int64
+}
 
-	// Get details for each container
-	for _, containerName := range containers {
-		info, err := getContainerInfo(containerName)
-		if err != nil {
-			fmt.Printf("Error getting info for container %s: %v\n", containerName, err)
-			continue
+// writeComposeExport writes the containers as a Docker Compose file with their
+// credentials moved to envPath (or left out when redacting). Returns the
+// variables holding credentials.
+func writeComposeExport(infos []ContainerInfo, path, envPath string, redact bool) ([]string, error) {
+	compose := buildComposeFile(infos)
+	lines, secrets := externalizeSecrets(compose, infos, redact)
+	if envPath != "" {
+		if err := writeEnvFile(envPath, lines); err != nil {
+			return nil, err
 		}
-		containerInfoMap[containerName] = info
 	}
+	return secrets, writeComposeFile(compose, path, 0644)
+}
 
-	// Generate Docker Compose YAML content
-	composeContent := generateComposeYAML(containerInfoMap)
-
-	// Get current working directory to save the file
-	cwd, err := os.Getwd()
+// writeComposeFile serializes a Compose file and checks that it reads back unchanged
+func writeComposeFile(compose ComposeFile, path string, mode os.FileMode) error {
+	content, err := yaml.Marshal(compose)
 	if err != nil {
-		fmt.Printf("Error getting current directory: %v\n", err)
-		return ""
+		return err
 	}
+	if err := os.WriteFile(path, content, mode); err != nil {
+		return err
+	}
+	if err := verifyComposeFile(path, compose); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	return nil
+}
 
-	// Create file path
-	filePath := filepath.Join(cwd, "docker-compose.yml")
-
-	// Trim trailing whitespace and newlines to ensure no empty line at the end
-	composeContent = strings.TrimRight(composeContent, "\n\r\t ")
+// containerInspect is the part of `docker inspect` the export reads
+type containerInspect struct {
+	Name   string
+	Image  string // image ID
+	Config struct {
+		Image        string
+		Env          []string
+		Cmd          []string
+		Entrypoint   []string
+		Labels       map[string]string
+		Healthcheck  *inspectHealthcheck
+		ExposedPorts map[string]struct{}
+	}
+	HostConfig struct {
+		PortBindings map[string][]struct {
+			HostIp   string
+			HostPort string
+		}
+		RestartPolicy struct {
+			Name string
+		}
+		Memory   int64
+		NanoCpus int64
+		ShmSize  int64
+		Ulimits  []struct {
+			Name string
+			Soft int64
+			Hard int64
+		}
+	}
+	Mounts []struct {
+		Type        string
+		Name        string
+		Source      string
+		Destination string
+		RW          bool
+	}
+	NetworkSettings struct {
+		Networks map[string]json.RawMessage
+	}
+}
 
-	// Write the YAML to file
-	err = os.WriteFile(filePath, []byte(composeContent), 0644)
-	if err != nil {
-		fmt.Printf("Error writing Docker Compose file: %v\n", err)
-		return ""
+// imageInspect is the part of `docker image inspect` the export compares against
+type imageInspect struct {
+	Config struct {
+		Env         []string
+		Cmd         []string
+		Entrypoint  []string
+		Labels      map[string]string
+		Healthcheck *inspectHealthcheck
 	}
+}
 
-	fmt.Printf("Docker Compose file created at: %s\n", filePath)
-	return filePath
+type inspectHealthcheck struct {
+	Test        []string
+	Interval    int64
+	Timeout     int64
+	StartPeriod int64
+	Retries     int
 }
 
 // getContainerInfo extracts all relevant information from a container
 func getContainerInfo(containerName string) (ContainerInfo, error) {
-	info := ContainerInfo{
-		Name: containerName,
-	}
+	info := ContainerInfo{Name: containerName}
 
-	// Get container image
-	cmd := exec.Command("docker", "inspect", "--format", "{{.Config.Image}}", containerName)
-	output, err := cmd.Output()
+	output, err := Command("inspect", "--type", "container", containerName).Output()
 	if err != nil {
-		return info, fmt.Errorf("error getting container image: %v", err)
+		return info, fmt.Errorf("error inspecting container: %v", err)
 	}
-	info.Image = strings.TrimSpace(string(output))
-
-	// Get exposed ports
-	cmd = exec.Command("docker", "inspect", "--format", "{{json .NetworkSettings.Ports}}", containerName)
-	output, err = cmd.Output()
-	if err != nil {
-		return info, fmt.Errorf("error getting container ports: %v", err)
-	}
-
-	// Simple port extraction (this could be more robust with JSON parsing)
-	ports := strings.TrimSpace(string(output))
-	if ports != "null" && ports != "{}" {
-		// Extract port mappings using another docker inspect command that's easier to parse
-		cmd = exec.Command("docker", "inspect", "--format", "{{range $p, $conf := .NetworkSettings.Ports}}{{if $conf}}{{(index $conf 0).HostPort}}:{{$p}}{{end}} {{end}}", containerName)
-		output, err = cmd.Output()
-		if err == nil {
-			portMappings := strings.Fields(strings.TrimSpace(string(output)))
-			for _, mapping := range portMappings {
-				if mapping != "" {
-					// Convert "hostPort:containerPort/tcp" to "hostPort:containerPort"
-					mapping = strings.Replace(mapping, "/tcp", "", 1)
-					mapping = strings.Replace(mapping, "/udp", "", 1)
-					info.Ports = append(info.Ports, mapping)
-				}
-			}
+	var containers []containerInspect
+	if err := json.Unmarshal(output, &containers); err != nil || len(containers) == 0 {
+		return info, fmt.Errorf("error parsing container details: %v", err)
+	}
+	container := containers[0]
+
+	// Settings inherited from the image are left out, the image brings them back
+	var image imageInspect
+	if output, err := Command("image", "inspect", container.Image).Output(); err == nil {
+		var images []imageInspect
+		if json.Unmarshal(output, &images) == nil && len(images) > 0 {
+			image = images[0]
 		}
 	}
 
-	// Get environment variables - using a different approach to preserve spaces in values
-	cmd = exec.Command("docker", "inspect", "--format", "{{json .Config.Env}}", containerName)
-	output, err = cmd.Output()
-	if err == nil {
-		// The output is a JSON array, remove the surrounding brackets and quotes
-		envString := strings.TrimSpace(string(output))
-		envString = strings.TrimPrefix(envString, "[")
-		envString = strings.TrimSuffix(envString, "]")
-
-		// Split by "," but be careful about commas within quotes
-		var envVars []string
-		inQuote := false
-		lastStart := 0
-
-		for i, c := range envString {
-			if c == '"' && (i == 0 || envString[i-1] != '\\') {
-				inQuote = !inQuote
-			} else if c == ',' && !inQuote {
-				part := envString[lastStart:i]
-				part = strings.TrimSpace(part)
-				if len(part) > 0 {
-					// Remove surrounding quotes
-					part = strings.TrimPrefix(part, "\"")
-					part = strings.TrimSuffix(part, "\"")
-					// Unescape escaped quotes
-					part = strings.ReplaceAll(part, "\\\"", "\"")
-					envVars = append(envVars, part)
-				}
-				lastStart = i + 1
-			}
+	info.Image = container.Config.Image
+	info.RestartPolicy = container.HostConfig.RestartPolicy.Name
+
+	for portProto, bindings := range container.HostConfig.PortBindings {
+		containerPort, protocol, _ := strings.Cut(portProto, "/")
+		for _, binding := range bindings {
+			info.Ports = append(info.Ports, PortMapping{
+				HostIP:        binding.HostIp,
+				HostPort:      binding.HostPort,
+				ContainerPort: containerPort,
+				Protocol:      protocol,
+			})
 		}
+	}
+	sort.Slice(info.Ports, func(i, j int) bool {
+		return portSortKey(info.Ports[i]) < portSortKey(info.Ports[j])
+	})
 
-		// Add the last part
-		if lastStart < len(envString) {
-			part := envString[lastStart:]
-			part = strings.TrimSpace(part)
-			part = strings.TrimPrefix(part, "\"")
-			part = strings.TrimSuffix(part, "\"")
-			part = strings.ReplaceAll(part, "\\\"", "\"")
-			if len(part) > 0 {
-				envVars = append(envVars, part)
-			}
-		}
+	// Includes the ports of the image, published or not
+	for portProto := range container.Config.ExposedPorts {
+		info.ExposedPorts = append(info.ExposedPorts, portProto)
+	}
+	sort.Strings(info.ExposedPorts)
 
-		// Filter environment variables - keep only meaningful ones for container functionality
-		var filteredEnvVars []string
-		isPhpMyAdmin := strings.Contains(strings.ToLower(containerName), "phpmyadmin")
-
-		// List of system/internal env vars to exclude
-		excludedPrefixes := []string{
-			"PATH=", "PHPIZE_DEPS=", "PHP_INI_DIR=",
-			"APACHE_CONFDIR=", "APACHE_ENVVARS=",
-			"PHP_CFLAGS=", "PHP_CPPFLAGS=", "PHP_LDFLAGS=",
-			"GPG_KEYS=", "PHP_VERSION=", "PHP_URL=", "PHP_ASC_URL=", "PHP_SHA256=",
-			"GOSU_VERSION=", "JSYAML_VERSION=", "JSYAML_CHECKSUM=",
-			"MONGO_PACKAGE=", "MONGO_REPO=", "MONGO_MAJOR=", "MONGO_VERSION=",
-			"GLIBC_TUNABLES=", "HOME=",
-			"VERSION=", "SHA256=", "URL=",
+	imageEnv := make(map[string]bool)
+	for _, env := range image.Config.Env {
+		imageEnv[env] = true
+	}
+	for _, env := range container.Config.Env {
+		if !imageEnv[env] {
+			info.EnvVars = append(info.EnvVars, env)
 		}
+	}
 
-		// For phpMyAdmin, only include specific variables
-		if isPhpMyAdmin {
-			for _, env := range envVars {
-				if strings.HasPrefix(env, "PMA_HOST=") ||
-					strings.HasPrefix(env, "PMA_PORT=") ||
-					strings.HasPrefix(env, "PMA_USER=") ||
-					strings.HasPrefix(env, "PMA_PASSWORD=") ||
-					strings.HasPrefix(env, "PMA_DATABASE=") ||
-					strings.HasPrefix(env, "PMA_ARBITRARY=") ||
-					strings.HasPrefix(env, "PMA_SSL=") ||
-					strings.HasPrefix(env, "PMA_SSL_VERIFY=") {
-					filteredEnvVars = append(filteredEnvVars, env)
-				}
-			}
-		} else {
-			// For other containers, exclude system variables
-			for _, env := range envVars {
-				exclude := false
-				for _, prefix := range excludedPrefixes {
-					if strings.HasPrefix(env, prefix) {
-						exclude = true
-						break
-					}
-				}
-				if !exclude {
-					filteredEnvVars = append(filteredEnvVars, env)
-				}
-			}
+	for _, mount := range container.Mounts {
+		volume := VolumeMount{Destination: mount.Destination, ReadOnly: !mount.RW}
+		switch mount.Type {
+		case "volume":
+			volume.Volume = mount.Name
+		case "bind":
+			volume.Source = mount.Source
+		default:
+			continue // tmpfs and others are not data worth exporting
 		}
+		info.Volumes = append(info.Volumes, volume)
+	}
 
-		info.EnvVars = filteredEnvVars
+	for network := range container.NetworkSettings.Networks {
+		info.Networks = append(info.Networks, network)
 	}
+	sort.Strings(info.Networks)
 
-	// Get volumes
-	cmd = exec.Command("docker", "inspect", "--format", "{{range .Mounts}}{{.Source}}:{{.Destination}} {{end}}", containerName)
-	output, err = cmd.Output()
-	if err == nil {
-		volumes := strings.Fields(strings.TrimSpace(string(output)))
-		info.Volumes = volumes
+	if !reflect.DeepEqual(container.Config.Entrypoint, image.Config.Entrypoint) {
+		info.Entrypoint = container.Config.Entrypoint
+	}
+	if !reflect.DeepEqual(container.Config.Cmd, image.Config.Cmd) || info.Entrypoint != nil {
+		// Compose resets the image's command when the entrypoint is overridden
+		info.Command = container.Config.Cmd
 	}
 
-	// Get networks
-	cmd = exec.Command("docker", "inspect", "--format", "{{range $k, $v := .NetworkSettings.Networks}}{{$k}} {{end}}", containerName)
-	output, err = cmd.Output()
-	if err == nil {
-		networks := strings.Fields(strings.TrimSpace(string(output)))
-		info.Networks = networks
+	for key, value := range container.Config.Labels {
+		if imageValue, ok := image.Config.Labels[key]; (ok && imageValue == value) || strings.HasPrefix(key, "com.docker.compose.") {
+			continue
+		}
+		if info.Labels == nil {
+			info.Labels = make(map[string]string)
+		}
+		info.Labels[key] = value
 	}
 
-	// Get restart policy
-	cmd = exec.Command("docker", "inspect", "--format", "{{.HostConfig.RestartPolicy.Name}}", containerName)
-	output, err = cmd.Output()
-	if err == nil {
-		info.RestartPolicy = strings.TrimSpace(string(output))
+	if check := container.Config.Healthcheck; check != nil && !reflect.DeepEqual(check, image.Config.Healthcheck) {
+		info.Healthcheck = &Healthcheck{
+			Test:        check.Test,
+			Interval:    time.Duration(check.Interval),
+			Timeout:     time.Duration(check.Timeout),
+			StartPeriod: time.Duration(check.StartPeriod),
+			Retries:     check.Retries,
+		}
 	}
 
-	// Get command if any
-	cmd = exec.Command("docker", "inspect", "--format", "{{if .Config.Cmd}}{{join .Config.Cmd \" \"}}{{end}}", containerName)
-	output, err = cmd.Output()
-	if err == nil {
-		info.Command = strings.TrimSpace(string(output))
+	info.MemoryBytes = container.HostConfig.Memory
+	info.NanoCPUs = container.HostConfig.NanoCpus
+	// 64MB is Docker's default /dev/shm size
+	if container.HostConfig.ShmSize != 64*1024*1024 {
+		info.ShmSizeBytes = container.HostConfig.ShmSize
+	}
+	for _, ulimit := range container.HostConfig.Ulimits {
+		info.Ulimits = append(info.Ulimits, Ulimit{Name: ulimit.Name, Soft: ulimit.Soft, Hard: ulimit.Hard})
 	}
 
 	return info, nil
 }
 
-// generateComposeYAML creates a Docker Compose YAML file from container info
-func generateComposeYAML(containers map[string]ContainerInfo) string {
-	// Template for Docker Compose file
-	const composeTemplate = `version: '3'
-
-services:
-{{- range $name, $container := . }}
-  {{ sanitizeName $name }}:
-    image: {{ $container.Image }}
-    container_name: {{ $name }}
-{{- if $container.RestartPolicy }}
-    restart: {{ $container.RestartPolicy }}
-{{- end }}
-{{- if $container.Command }}
-    command: {{ $container.Command }}
-{{- end }}
-{{- if $container.Ports }}
-    ports:
-{{- range $container.Ports }}
-      - "{{ . }}"
-{{- end }}
-{{- end }}
-{{- if $container.EnvVars }}
-    environment:
-{{ formatEnvironment $container.EnvVars | indent 6 }}
-{{- end }}
-{{- if $container.Volumes }}
-    volumes:
-{{- range $container.Volumes }}
-      - {{ . }}
-{{- end }}
-{{- end }}
-{{- if $container.Networks }}
-    networks:
-{{- range $container.Networks }}
-      - {{ . }}
-{{- end }}
-{{- end }}
-{{- end }}
-
-networks:
-  ContainDB-Network:
-    external: true
-`
-
-	// Create template with custom functions
-	tmpl, err := template.New("compose").Funcs(template.FuncMap{
-		"sanitizeName": func(name string) string {
-			// Replace problematic characters in service names
-			return strings.ReplaceAll(name, "-", "_")
-		},
-		"formatEnvironment": func(envVars []string) string {
-			var result strings.Builder
-			processedVars := make(map[string]bool) // Track processed variables to avoid duplicates
-
-			// Special handling for GPG_KEYS and similar variables with spaces
-			for _, envVar := range envVars {
-				parts := strings.SplitN(envVar, "=", 2)
-				if len(parts) != 2 {
-					// If it doesn't have an equals sign, just add it as is
-					if !processedVars[envVar] {
-						processedVars[envVar] = true
-						result.WriteString(fmt.Sprintf("%s: ''\n", envVar))
-					}
-					continue
-				}
+func portSortKey(port PortMapping) string {
+	number, _ := strconv.Atoi(port.ContainerPort)
+	return fmt.Sprintf("%06d/%s/%s", number, port.Protocol, port.HostIP)
+}
+
+// buildComposeFile turns the inspected containers into a Compose file
+func buildComposeFile(infos []ContainerInfo) ComposeFile {
+	compose := ComposeFile{Services: make(map[string]ComposeService)}
+
+	for _, info := range infos {
+		service := ComposeService{
+			Image:         info.Image,
+			ContainerName: info.Name,
+			Entrypoint:    composeEscapeAll(info.Entrypoint),
+			Command:       composeEscapeAll(info.Command),
+			Networks:      info.Networks,
+		}
+		for key, value := range info.Labels {
+			if service.Labels == nil {
+				service.Labels = make(map[string]string)
+			}
+			service.Labels[key] = composeEscape(value)
+		}
+		if info.RestartPolicy != "no" {
+			service.Restart = info.RestartPolicy
+		}
 
-				key := parts[0]
-				value := parts[1]
+		for _, port := range info.Ports {
+			service.Ports = append(service.Ports, port.String())
+		}
 
-				// Skip if we've already processed this key
-				if processedVars[key] {
-					continue
+		for _, envVar := range info.EnvVars {
+			key, value, _ := strings.Cut(envVar, "=")
+			if service.Environment == nil {
+				service.Environment = make(map[string]string)
+			}
+			service.Environment[key] = composeEscape(value)
+		}
+
+		for _, volume := range info.Volumes {
+			source := volume.Source
+			if volume.Volume != "" {
+				source = volume.Volume
+				if compose.Volumes == nil {
+					compose.Volumes = make(map[string]ComposeVolume)
 				}
+				compose.Volumes[volume.Volume] = ComposeVolume{Name: volume.Volume}
+			}
+			mount := source + ":" + volume.Destination
+			if volume.ReadOnly {
+				mount += ":ro"
+			}
+			service.Volumes = append(service.Volumes, mount)
+		}
 
-				processedVars[key] = true
+		for _, network := range info.Networks {
+			if compose.Networks == nil {
+				compose.Networks = make(map[string]ComposeNetwork)
+			}
+			// The networks already exist on a ContainDB machine
+			compose.Networks[network] = ComposeNetwork{External: true}
+		}
 
-				// Always quote the value to ensure YAML compatibility
-				// Use single quotes and escape any single quotes within the value
-				escapedValue := strings.Replace(value, "'", "''", -1)
-				result.WriteString(fmt.Sprintf("%s: '%s'\n", key, escapedValue))
+		if check := info.Healthcheck; check != nil {
+			service.Healthcheck = &ComposeHealthcheck{
+				Test:        composeEscapeAll(check.Test),
+				Interval:    composeDuration(check.Interval),
+				Timeout:     composeDuration(check.Timeout),
+				StartPeriod: composeDuration(check.StartPeriod),
+				Retries:     check.Retries,
 			}
+		}
 
-			return result.String()
-		},
-		"indent": func(spaces int, text string) string {
-			pad := strings.Repeat(" ", spaces)
-			lines := strings.Split(text, "\n")
-			for i, line := range lines {
-				if line != "" {
-					lines[i] = pad + line
-				}
+		if info.MemoryBytes > 0 {
+			service.MemLimit = composeBytes(info.MemoryBytes)
+		}
+		if info.NanoCPUs > 0 {
+			service.CPUs = strconv.FormatFloat(float64(info.NanoCPUs)/1e9, 'f', -1, 64)
+		}
+		if info.ShmSizeBytes > 0 {
+			service.ShmSize = composeBytes(info.ShmSizeBytes)
+		}
+		for _, ulimit := range info.Ulimits {
+			if service.Ulimits == nil {
+				service.Ulimits = make(map[string]ComposeUlimit)
 			}
-			return strings.Join(lines, "\n")
-		},
-	}).Parse(composeTemplate)
-	if err != nil {
-		fmt.Printf("Error creating template: %v\n", err)
-		return ""
+			service.Ulimits[ulimit.Name] = ComposeUlimit{Soft: ulimit.Soft, Hard: ulimit.Hard}
+		}
+
+		compose.Services[composeServiceName(info.Name)] = service
 	}
+	return compose
+}
 
-	// Execute template
-	var result strings.Builder
-	err = tmpl.Execute(&result, containers)
-	if err != nil {
-		fmt.Printf("Error executing template: %v\n", err)
+// String returns the short Compose syntax of the mapping, e.g. "127.0.0.1:8080:80/udp"
+func (p PortMapping) String() string {
+	mapping := p.ContainerPort
+	if p.HostPort != "" {
+		mapping = p.HostPort + ":" + mapping
+	}
+	if p.HostIP != "" && p.HostIP != "0.0.0.0" {
+		hostIP := p.HostIP
+		if strings.Contains(hostIP, ":") {
+			hostIP = "[" + hostIP + "]"
+		}
+		mapping = hostIP + ":" + mapping
+	}
+	if p.Protocol != "" && p.Protocol != "tcp" {
+		mapping += "/" + p.Protocol
+	}
+	return mapping
+}
+
+// composeEscape keeps Compose from interpolating a literal "$" in a value
+func composeEscape(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
+func composeEscapeAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = composeEscape(value)
+	}
+	return escaped
+}
+
+// composeServiceName replaces characters that are awkward in service names
+func composeServiceName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// composeDuration formats a duration for Compose ("1m30s"); zero is left out
+func composeDuration(d time.Duration) string {
+	if d == 0 {
 		return ""
 	}
+	return d.String()
+}
 
-	return result.String()
+// composeBytes formats a byte size for Compose, using the largest exact unit
+func composeBytes(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}} {
+		if bytes%unit.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%db", bytes)
+}
+
+// verifyComposeFile reads the written file back and checks that it describes
+// exactly the services that were exported, so quoting never changes a value
+func verifyComposeFile(path string, expected ComposeFile) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var written ComposeFile
+	if err := yaml.Unmarshal(data, &written); err != nil {
+		return fmt.Errorf("the exported file is not valid YAML: %v", err)
+	}
+	for name, service := range expected.Services {
+		if !reflect.DeepEqual(written.Services[name], service) {
+			return fmt.Errorf("service '%s' does not read back as exported, check %s before using it", name, path)
+		}
+	}
+	return nil
 }
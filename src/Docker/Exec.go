@@ -0,0 +1,50 @@
+package Docker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ExecInContainer runs a command inside a running container and returns its
+// trimmed output. env entries (KEY=value) are only set for that command.
+func ExecInContainer(name string, env []string, command ...string) (string, error) {
+	return ExecWithInput(name, nil, env, command...)
+}
+
+// ExecWithInput is ExecInContainer with input fed to the command's stdin
+func ExecWithInput(name string, input []byte, env []string, command ...string) (string, error) {
+	args := []string{"exec"}
+	if input != nil {
+		args = append(args, "-i")
+	}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	args = append(args, name)
+	args = append(args, command...)
+
+	cmd := Command(args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		if output != "" {
+			return output, fmt.Errorf("%s", output)
+		}
+		return "", err
+	}
+	return output, nil
+}
+
+// WriteFileInContainer writes content to path inside a running container
+func WriteFileInContainer(name, path, content string) error {
+	cmd := Command("exec", "-i", name, "sh", "-c", fmt.Sprintf("cat > '%s'", path))
+	cmd.Stdin = strings.NewReader(content)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write %s in %s: %v %s", path, name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+package Docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ExportOptions selects what is exported, in which format and where to
+type ExportOptions struct {
+	Format     string   // compose, env, script, stack, k8s or helm
+	Containers []string // containers to export; nil exports every running ContainDB container
+	Output     string   // file (directory for helm); empty uses DefaultExportPath in the current directory
+	Backup     bool     // rename existing output files instead of refusing to overwrite them
+	Redact     bool     // leave credentials out of the export (compose and env formats)
+}
+
+// ExportResult lists what an export wrote
+type ExportResult struct {
+	Paths   []string // the export, followed by its .env file when one was written
+	Secrets []string // variables the credentials were moved to
+}
+
+// DefaultExportPath returns the file name an export format is written to
+func DefaultExportPath(format string) string {
+	switch format {
+	case "script":
+		return "containdb-run.sh"
+	case "stack":
+		return "containdb-stack.yml"
+	case "k8s":
+		return "containdb-k8s.yaml"
+	case "helm":
+		return "containdb-chart"
+	}
+	return "docker-compose.yml"
+}
+
+// ExportPaths returns every file an export writes, so they can be checked before writing
+func ExportPaths(opts ExportOptions) []string {
+	path := opts.Output
+	if path == "" {
+		path = DefaultExportPath(opts.Format)
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	// Compose exports keep credentials in an .env file, unless they are redacted
+	if opts.Format == "env" || (opts.Format == "compose" && !opts.Redact) {
+		return []string{path, filepath.Join(filepath.Dir(path), ".env")}
+	}
+	return []string{path}
+}
+
+// ExportServices writes the selected containers in the requested format and
+// returns what was written. Existing files are backed up when opts.Backup is
+// set and left alone otherwise.
+func ExportServices(opts ExportOptions) (ExportResult, error) {
+	result := ExportResult{}
+	if opts.Redact && opts.Format != "compose" && opts.Format != "env" {
+		return result, fmt.Errorf("--redact is only supported by the compose and env formats")
+	}
+
+	containers := opts.Containers
+	if containers == nil {
+		running, err := ListRunningDatabases()
+		if err != nil {
+			return result, fmt.Errorf("error listing containers: %v", err)
+		}
+		containers = running
+	}
+	if len(containers) == 0 {
+		return result, fmt.Errorf("no containers to export on ContainDB-Network")
+	}
+
+	paths := ExportPaths(opts)
+	for _, path := range paths {
+		if err := prepareExportPath(path, opts.Backup); err != nil {
+			return result, err
+		}
+	}
+
+	// Get details for each container
+	var infos []ContainerInfo
+	for _, containerName := range containers {
+		info, err := getContainerInfo(containerName)
+		if err != nil {
+			fmt.Printf("Error getting info for container %s: %v\n", containerName, err)
+			continue
+		}
+		infos = append(infos, info)
+	}
+	if len(infos) == 0 {
+		return result, fmt.Errorf("none of the selected containers could be inspected")
+	}
+
+	var err error
+	switch opts.Format {
+	case "compose":
+		envPath := ""
+		if len(paths) > 1 {
+			envPath = paths[1]
+		}
+		result.Secrets, err = writeComposeExport(infos, paths[0], envPath, opts.Redact)
+	case "env":
+		result.Secrets, err = writeEnvSplitExport(infos, paths[0], paths[1], opts.Redact)
+	case "script":
+		err = writeRunScript(infos, paths[0])
+	case "stack":
+		err = writeStackFile(infos, paths[0])
+	case "k8s", "helm":
+		err = writeKubernetesExport(infos, opts.Format, paths[0])
+	default:
+		err = fmt.Errorf("unknown export format '%s'", opts.Format)
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Paths = paths
+	return result, nil
+}
+
+// SelectContainers applies --only and --exclude to the running containers
+func SelectContainers(all, only, exclude []string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, name := range all {
+		known[name] = true
+	}
+	for _, name := range append(append([]string{}, only...), exclude...) {
+		if !known[name] {
+			return nil, fmt.Errorf("'%s' is not a running ContainDB container", name)
+		}
+	}
+
+	selected := all
+	if len(only) > 0 {
+		selected = only
+	}
+	excluded := make(map[string]bool)
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+	var result []string
+	for _, name := range selected {
+		if !excluded[name] {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// prepareExportPath makes sure path can be written: missing parent
+// directories are created and an existing file is backed up or refused
+func prepareExportPath(path string, backup bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if !backup {
+		return fmt.Errorf("%s already exists; choose another output path or back it up with --backup", path)
+	}
+	backupPath := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, backupPath); err != nil {
+		return fmt.Errorf("failed to back up %s: %v", path, err)
+	}
+	fmt.Printf("📦 Backed up the existing %s to %s\n", filepath.Base(path), backupPath)
+	return nil
+}
+
+// writeEnvSplitExport writes a compose file whose environment values all
+// reference variables of an .env file next to it, which Compose reads on its
+// own. Returns the variables holding credentials.
+func writeEnvSplitExport(infos []ContainerInfo, composePath, envPath string, redact bool) ([]string, error) {
+	compose := buildComposeFile(infos)
+	lines, secrets := externalizeSecrets(compose, infos, redact)
+	for _, name := range sortedServiceNames(compose) {
+		service := compose.Services[name]
+		for _, key := range sortedKeys(service.Environment) {
+			if strings.HasPrefix(service.Environment[key], "${") {
+				continue
+			}
+			variable := envVariableName(service.ContainerName, key)
+			// Values in the compose file are escaped for interpolation, .env values are not
+			lines = append(lines, variable+"="+dotenvQuote(strings.ReplaceAll(service.Environment[key], "$$", "$")))
+			service.Environment[key] = "${" + variable + "}"
+		}
+	}
+
+	if err := writeEnvFile(envPath, lines); err != nil {
+		return nil, err
+	}
+	return secrets, writeComposeFile(compose, composePath, 0644)
+}
+
+// writeEnvFile writes the .env of an export, readable by the owner only
+func writeEnvFile(path string, lines []string) error {
+	content := "# Written by ContainDB export, keep it out of version control.\n"
+	if len(lines) > 0 {
+		content += strings.Join(lines, "\n") + "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0600)
+}
+
+// GitRepository returns the root of the git repository path would be written
+// into, or "" when it is outside of one
+func GitRepository(path string) string {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return ""
+	}
+	for {
+		// .git is a directory, or a file in worktrees and submodules
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// IsGitIgnored reports whether git ignores path. Without git nothing counts as ignored.
+func IsGitIgnored(path string) bool {
+	return exec.Command("git", "-C", filepath.Dir(path), "check-ignore", "-q", path).Run() == nil
+}
+
+// envVariableName returns the .env variable holding key of a container,
+// prefixed so equal keys of different containers do not collide
+func envVariableName(container, key string) string {
+	prefix := regexp.MustCompile(`[^A-Z0-9]+`).ReplaceAllString(strings.ToUpper(container), "_")
+	return strings.Trim(prefix, "_") + "_" + key
+}
+
+// dotenvQuote quotes a value for an .env file; single quotes keep it literal
+func dotenvQuote(value string) string {
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "$", `\$`)
+	return `"` + value + `"`
+}
+
+func sortedServiceNames(compose ComposeFile) []string {
+	names := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeRunScript writes a shell script recreating the containers with plain
+// `docker run` commands, for machines without Compose
+func writeRunScript(infos []ContainerInfo, path string) error {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString("# Recreates the ContainDB containers exported on " + time.Now().Format("2006-01-02") + "\n")
+	script.WriteString("# Set DOCKER=podman or DOCKER=nerdctl to use another runtime\n")
+	script.WriteString("set -e\n\nDOCKER=${DOCKER:-docker}\n\n")
+
+	networks := make(map[string]bool)
+	for _, info := range infos {
+		for _, network := range info.Networks {
+			networks[network] = true
+		}
+	}
+	for _, network := range sortedKeysBool(networks) {
+		fmt.Fprintf(&script, "$DOCKER network inspect %s >/dev/null 2>&1 || $DOCKER network create %s\n", shellQuote(network), shellQuote(network))
+	}
+
+	for _, info := range infos {
+		flags, positional := dockerRunArgs(info)
+		fmt.Fprintf(&script, "\necho %s\n$DOCKER run", shellQuote("Starting "+info.Name+"..."))
+		for _, arg := range flags {
+			if strings.HasPrefix(arg, "-") {
+				script.WriteString(" \\\n  " + arg)
+			} else {
+				script.WriteString(" " + shellQuote(arg))
+			}
+		}
+		script.WriteString(" \\\n  " + shellJoin(positional) + "\n")
+		// Further networks can only be attached once the container exists
+		for _, network := range info.Networks[min(1, len(info.Networks)):] {
+			fmt.Fprintf(&script, "$DOCKER network connect %s %s\n", shellQuote(network), shellQuote(info.Name))
+		}
+	}
+
+	// Executable, and private because it holds the containers' credentials
+	return os.WriteFile(path, []byte(script.String()), 0700)
+}
+
+// dockerRunArgs returns the `docker run` flags recreating a container, and
+// the image with its command
+func dockerRunArgs(info ContainerInfo) ([]string, []string) {
+	args := []string{"-d", "--name", info.Name}
+	if len(info.Networks) > 0 {
+		args = append(args, "--network", info.Networks[0])
+	}
+	if info.RestartPolicy != "" && info.RestartPolicy != "no" {
+		args = append(args, "--restart", info.RestartPolicy)
+	}
+	for _, port := range info.Ports {
+		args = append(args, "-p", port.String())
+	}
+	for _, envVar := range info.EnvVars {
+		args = append(args, "-e", envVar)
+	}
+	for _, volume := range info.Volumes {
+		source := volume.Source
+		if volume.Volume != "" {
+			source = volume.Volume
+		}
+		mount := source + ":" + volume.Destination
+		if volume.ReadOnly {
+			mount += ":ro"
+		}
+		args = append(args, "-v", mount)
+	}
+	for _, key := range sortedKeys(info.Labels) {
+		args = append(args, "--label", key+"="+info.Labels[key])
+	}
+	if info.MemoryBytes > 0 {
+		args = append(args, "--memory", composeBytes(info.MemoryBytes))
+	}
+	if info.NanoCPUs > 0 {
+		args = append(args, "--cpus", fmt.Sprintf("%g", float64(info.NanoCPUs)/1e9))
+	}
+	if info.ShmSizeBytes > 0 {
+		args = append(args, "--shm-size", composeBytes(info.ShmSizeBytes))
+	}
+	for _, ulimit := range info.Ulimits {
+		args = append(args, "--ulimit", fmt.Sprintf("%s=%d:%d", ulimit.Name, ulimit.Soft, ulimit.Hard))
+	}
+	if check := info.Healthcheck; check != nil && len(check.Test) > 0 {
+		switch check.Test[0] {
+		case "NONE":
+			args = append(args, "--no-healthcheck")
+		case "CMD-SHELL":
+			args = append(args, "--health-cmd", strings.Join(check.Test[1:], " "))
+		default:
+			args = append(args, "--health-cmd", shellJoin(check.Test[1:]))
+		}
+		if check.Interval > 0 {
+			args = append(args, "--health-interval", check.Interval.String())
+		}
+		if check.Timeout > 0 {
+			args = append(args, "--health-timeout", check.Timeout.String())
+		}
+		if check.StartPeriod > 0 {
+			args = append(args, "--health-start-period", check.StartPeriod.String())
+		}
+		if check.Retries > 0 {
+			args = append(args, "--health-retries", fmt.Sprint(check.Retries))
+		}
+	}
+
+	// --entrypoint takes a single executable; the rest of it goes in front of the command
+	command := info.Command
+	if len(info.Entrypoint) > 0 {
+		args = append(args, "--entrypoint", info.Entrypoint[0])
+		command = append(append([]string{}, info.Entrypoint[1:]...), command...)
+	}
+	return args, append([]string{info.Image}, command...)
+}
+
+// shellQuote quotes a word for a POSIX shell
+func shellQuote(word string) string {
+	if word != "" && regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`).MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+func shellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = shellQuote(word)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func sortedKeysBool(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// StackFile is a ContainDB stack: the exported instances with the engine
+// ContainDB installed them as, importable with `containdb --import`
+type StackFile struct {
+	Version   int             `yaml:"containdb_stack"`
+	Exported  string          `yaml:"exported,omitempty"`
+	Instances []StackInstance `yaml:"instances"`
+}
+
+// StackInstance is one container of a StackFile
+type StackInstance struct {
+	Name           string `yaml:"name"`
+	Engine         string `yaml:"engine,omitempty"` // empty for management tools
+	ComposeService `yaml:",inline"`
+}
+
+// writeStackFile writes the containers as a ContainDB stack file
+func writeStackFile(infos []ContainerInfo, path string) error {
+	compose := buildComposeFile(infos)
+	stack := StackFile{Version: 1, Exported: time.Now().Format(time.RFC3339)}
+	for _, name := range sortedServiceNames(compose) {
+		service := compose.Services[name]
+		instance := StackInstance{Name: service.ContainerName, Engine: DetectEngine(service.ContainerName), ComposeService: service}
+		instance.ContainerName = ""
+		stack.Instances = append(stack.Instances, instance)
+	}
+
+	content, err := yaml.Marshal(stack)
+	if err != nil {
+		return err
+	}
+	// The instances' environment holds their credentials
+	return os.WriteFile(path, content, 0600)
+}
+
+// isStackFile reports whether a file passed to --import is a ContainDB stack
+func isStackFile(data []byte) bool {
+	var header struct {
+		Version int `yaml:"containdb_stack"`
+	}
+	return yaml.Unmarshal(data, &header) == nil && header.Version > 0
+}
+
+// stackToCompose converts a stack file into the Compose file the import runs
+func stackToCompose(data []byte) ([]byte, error) {
+	var stack StackFile
+	if err := yaml.Unmarshal(data, &stack); err != nil {
+		return nil, err
+	}
+	compose := ComposeFile{Services: make(map[string]ComposeService)}
+	for _, instance := range stack.Instances {
+		service := instance.ComposeService
+		service.ContainerName = instance.Name
+		for _, mount := range service.Volumes {
+			if volume := NamedVolume(mount); volume != "" {
+				if compose.Volumes == nil {
+					compose.Volumes = make(map[string]ComposeVolume)
+				}
+				compose.Volumes[volume] = ComposeVolume{Name: volume}
+			}
+		}
+		for _, network := range service.Networks {
+			if compose.Networks == nil {
+				compose.Networks = make(map[string]ComposeNetwork)
+			}
+			compose.Networks[network] = ComposeNetwork{External: true}
+		}
+		compose.Services[composeServiceName(instance.Name)] = service
+	}
+	return yaml.Marshal(compose)
+}
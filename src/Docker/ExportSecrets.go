@@ -0,0 +1,82 @@
+package Docker
+
+import (
+	"regexp"
+	"strings"
+)
+
+// engineSecretEnv lists the environment variables each engine keeps its
+// credentials in; "" holds the management tools
+var engineSecretEnv = map[string][]string{
+	"mysql":         {"MYSQL_ROOT_PASSWORD", "MYSQL_PASSWORD"},
+	"mariadb":       {"MARIADB_ROOT_PASSWORD", "MARIADB_PASSWORD", "MYSQL_ROOT_PASSWORD", "MYSQL_PASSWORD"},
+	"postgresql":    {"POSTGRES_PASSWORD"},
+	"pgvector":      {"POSTGRES_PASSWORD"},
+	"mongodb":       {"MONGO_INITDB_ROOT_PASSWORD"},
+	"redis":         {"REDIS_PASSWORD"},
+	"redis-stack":   {"REDIS_ARGS", "REDIS_PASSWORD"},
+	"valkey":        {"REDIS_PASSWORD"},
+	"keydb":         {"REDIS_PASSWORD"},
+	"elasticsearch": {"ELASTIC_PASSWORD"},
+	"opensearch":    {"OPENSEARCH_INITIAL_ADMIN_PASSWORD"},
+	"couchdb":       {"COUCHDB_PASSWORD"},
+	"couchbase":     {"COUCHBASE_ADMINISTRATOR_PASSWORD"},
+	"etcd":          {"ETCD_ROOT_PASSWORD"},
+	"qdrant":        {"QDRANT__SERVICE__API_KEY", "QDRANT__SERVICE__READ_ONLY_API_KEY"},
+	"weaviate":      {"AUTHENTICATION_APIKEY_ALLOWED_KEYS"},
+	"milvus":        {"MILVUS_ROOT_PASSWORD", "MINIO_SECRET_KEY"},
+	"chroma":        {"CHROMA_SERVER_AUTHN_CREDENTIALS"},
+	"marqo":         {"MARQO_API_KEY"},
+	"typesense":     {"TYPESENSE_API_KEY"},
+	"": {
+		"PMA_PASSWORD", "PGADMIN_DEFAULT_PASSWORD", "ME_CONFIG_BASICAUTH_PASSWORD",
+		"ME_CONFIG_MONGODB_ADMINPASSWORD", "ME_CONFIG_MONGODB_URL", "RI_REDIS_PASSWORD",
+		"ELASTICSEARCH_PASSWORD", "OPENSEARCH_PASSWORD",
+	},
+}
+
+// secretEnvPattern catches credentials of images ContainDB does not know
+var secretEnvPattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|_PASS$|SECRET|TOKEN|API_?KEY|CREDENTIAL)`)
+
+// urlCredentialsPattern matches connection strings carrying a password, e.g. mongodb://root:pw@host
+var urlCredentialsPattern = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://[^/@\s]*:[^/@\s]+@`)
+
+// isSecretEnv reports whether an environment variable of an engine holds a credential
+func isSecretEnv(engine, key, value string) bool {
+	for _, secret := range engineSecretEnv[engine] {
+		if key == secret {
+			return true
+		}
+	}
+	return secretEnvPattern.MatchString(key) || urlCredentialsPattern.MatchString(value)
+}
+
+// externalizeSecrets replaces the credentials in the compose services with
+// ${VAR} references and returns the .env lines holding their values. Redacted
+// secrets become required references and get no .env line.
+func externalizeSecrets(compose ComposeFile, infos []ContainerInfo, redact bool) (lines, variables []string) {
+	engines := make(map[string]string)
+	for _, info := range infos {
+		engines[info.Name] = engineOfImage(info.Image)
+	}
+	for _, name := range sortedServiceNames(compose) {
+		service := compose.Services[name]
+		engine := engines[service.ContainerName]
+		for _, key := range sortedKeys(service.Environment) {
+			// Values in the compose file are escaped for interpolation, .env values are not
+			value := strings.ReplaceAll(service.Environment[key], "$$", "$")
+			if !isSecretEnv(engine, key, value) {
+				continue
+			}
+			variable := envVariableName(service.ContainerName, key)
+			variables = append(variables, variable)
+			if redact {
+				service.Environment[key] = "${" + variable + ":?redacted}"
+				continue
+			}
+			lines = append(lines, variable+"="+dotenvQuote(value))
+			service.Environment[key] = "${" + variable + "}"
+		}
+	}
+	return lines, variables
+}
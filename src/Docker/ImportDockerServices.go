@@ -3,31 +3,42 @@ package Docker
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
-// DockerComposeConfig represents the structure of a docker-compose.yml file
-type DockerComposeConfig struct {
-	Version  string                          `yaml:"version"`
-	Services map[string]DockerComposeService `yaml:"services"`
-	Volumes  map[string]interface{}          `yaml:"volumes"`
+// ManagedLabel marks containers and volumes created by ContainDB from a compose import
+const ManagedLabel = "containdb.managed"
+
+// importPlan describes what an import will do before anything is started
+type importPlan struct {
+	Project       string
+	Services      []plannedService
+	Volumes       []string          // volumes compose will create
+	NameConflicts []string          // container names taken by other containers
+	PortConflicts []string          // human readable, one per conflicting host port
+	Remaps        map[string]string // "service/hostPort" -> free host port
+	Unmanaged     map[string]string // service -> reason it cannot join ContainDB-Network
 }
 
-// DockerComposeService represents a service in a docker-compose.yml file
-type DockerComposeService struct {
-	Ports       []string          `yaml:"ports"`
-	Volumes     []string          `yaml:"volumes"`
-	Environment map[string]string `yaml:"environment"`
-	// Add other fields as needed
+type plannedService struct {
+	Name          string
+	ContainerName string
+	Image         string
+	Ports         []string
 }
 
-// ImportDockerServices imports services from a docker-compose.yml file
+// ImportDockerServices imports services from a docker-compose.yml file. It
+// validates the file, prints a plan with the conflicts it found, offers to
+// remap taken host ports, and starts the services as ContainDB instances:
+// attached to ContainDB-Network with a fixed container name and a managed label.
 func ImportDockerServices(composeFilePath string) error {
 	// Check if Docker is installed
 	if !IsDockerInstalled() {
@@ -35,98 +46,408 @@ func ImportDockerServices(composeFilePath string) error {
 	}
 
 	// Read and parse docker-compose.yml
-	composeData, err := ioutil.ReadFile(composeFilePath)
+	composeData, err := os.ReadFile(composeFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to read compose file: %v", err)
 	}
+	if isStackFile(composeData) {
+		fmt.Println("Reading ContainDB stack file...")
+		if composeData, err = stackToCompose(composeData); err != nil {
+			return fmt.Errorf("failed to parse stack file: %v", err)
+		}
+	}
 
-	var composeConfig DockerComposeConfig
-	err = yaml.Unmarshal(composeData, &composeConfig)
-	if err != nil {
+	var compose ComposeFile
+	if err := yaml.Unmarshal(composeData, &compose); err != nil {
+		return fmt.Errorf("failed to parse compose file: %v", err)
+	}
+	if err := validateCompose(compose); err != nil {
+		return fmt.Errorf("invalid compose file: %v", err)
+	}
+	// The file is rewritten from its generic form, so settings ContainDB does not model are kept
+	var document yamlMap
+	if err := yaml.Unmarshal(composeData, &document); err != nil {
 		return fmt.Errorf("failed to parse compose file: %v", err)
 	}
 
-	// Check if services are already running
-	fmt.Println("Checking if services are already running...")
-	runningContainers, err := getRunningContainers()
+	absPath, err := filepath.Abs(composeFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to get running containers: %v", err)
+		return err
+	}
+	fmt.Println("Checking ports, volumes and existing containers...")
+	plan := planImport(compose, composeProjectName(filepath.Dir(absPath)))
+	printImportPlan(plan)
+
+	if len(plan.NameConflicts) > 0 {
+		return errors.New("remove or rename the conflicting containers, or set another container_name, and import again")
+	}
+	if len(plan.PortConflicts) > 0 && !AskYesNo("Remap the conflicting host ports to the free ports shown above?") {
+		plan.Remaps = nil
+		fmt.Println("⚠️  Keeping the original ports; the services using taken ports will fail to start.")
+	}
+	if !AskYesNo("Proceed with the import?") {
+		return errors.New("import cancelled")
+	}
+
+	rewriteComposeDocument(document, plan)
+	rewritten, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to prepare compose file: %v", err)
+	}
+	// Written next to the original so relative paths and the project name stay the same
+	importPath := filepath.Join(filepath.Dir(absPath), ".containdb-import-"+filepath.Base(absPath))
+	if err := os.WriteFile(importPath, rewritten, 0600); err != nil {
+		return fmt.Errorf("failed to prepare compose file: %v", err)
 	}
+	defer os.Remove(importPath)
 
-	for serviceName := range composeConfig.Services {
-		for _, containerInfo := range runningContainers {
-			if strings.Contains(containerInfo, serviceName) {
-				fmt.Printf("Warning: Service '%s' appears to be already running\n", serviceName)
+	// Start services using docker-compose up -d
+	fmt.Println("Starting services...")
+	cmd := ComposeCommand("-f", importPath, "up", "-d")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to start services: %v", err)
+	}
+
+	fmt.Println("ℹ️  The imported services are ContainDB instances now: list, manage, remove them or attach tools from the menu.")
+	return nil
+}
+
+// validateCompose checks the parts of the file the import relies on
+func validateCompose(compose ComposeFile) error {
+	if len(compose.Services) == 0 {
+		return errors.New("no services defined")
+	}
+	for name, service := range compose.Services {
+		if service.Image == "" && service.Build == nil {
+			return fmt.Errorf("service '%s' has neither image nor build", name)
+		}
+		for _, mapping := range service.Ports {
+			if _, err := ParseComposePort(mapping); err != nil {
+				return fmt.Errorf("service '%s': %v", name, err)
 			}
 		}
+		for _, dependency := range service.DependsOn {
+			if _, ok := compose.Services[dependency]; !ok {
+				return fmt.Errorf("service '%s' depends on undefined service '%s'", name, dependency)
+			}
+		}
+		for _, network := range service.Networks {
+			if _, ok := compose.Networks[network]; !ok && network != "default" {
+				return fmt.Errorf("service '%s' uses undefined network '%s'", name, network)
+			}
+		}
+		for _, mount := range service.Volumes {
+			if volume := NamedVolume(mount); volume != "" {
+				if _, ok := compose.Volumes[volume]; !ok {
+					return fmt.Errorf("service '%s' uses undefined volume '%s'", name, volume)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// planImport works out the containers, volumes and port changes of an import
+func planImport(compose ComposeFile, project string) importPlan {
+	plan := importPlan{
+		Project:   project,
+		Remaps:    make(map[string]string),
+		Unmanaged: make(map[string]string),
+	}
+
+	names := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	existing, _ := getAllContainers()
+	taken := make(map[string]bool) // host ports claimed by earlier services of the file
+	for _, name := range names {
+		service := compose.Services[name]
+		planned := plannedService{Name: name, ContainerName: service.ContainerName, Image: service.Image, Ports: service.Ports}
+		if planned.ContainerName == "" {
+			planned.ContainerName = name
+		}
+		if planned.Image == "" {
+			planned.Image = "(built from source)"
+		}
+		if service.NetworkMode != "" {
+			plan.Unmanaged[name] = "network_mode: " + service.NetworkMode
+		}
+
+		if existing[planned.ContainerName] && containerProject(planned.ContainerName) != project {
+			conflict := planned.ContainerName
+			if _, err := GetContainDBInstance(planned.ContainerName); err == nil {
+				conflict += " (existing ContainDB instance)"
+			}
+			plan.NameConflicts = append(plan.NameConflicts, conflict)
+		}
+
+		for _, mapping := range service.Ports {
+			port, _ := ParseComposePort(mapping)
+			if _, err := strconv.Atoi(port.HostPort); err != nil {
+				continue // random or ranged host ports are left to the engine
+			}
+			reason := ""
+			if taken[port.HostPort] {
+				reason = "is used twice in this file"
+			} else if containerProject(planned.ContainerName) == project && existing[planned.ContainerName] {
+				// Published by the container being replaced
+			} else if check := CheckPort(port.HostPort); check.Status != CheckPass {
+				reason = check.Message
+			}
+			if reason == "" {
+				taken[port.HostPort] = true
+				continue
+			}
+			free := nextFreePort(port.HostPort, taken)
+			taken[free] = true
+			plan.Remaps[name+"/"+port.HostPort] = free
+			plan.PortConflicts = append(plan.PortConflicts, fmt.Sprintf("%s: port %s %s → remap to %s", name, port.HostPort, reason, free))
+		}
+		plan.Services = append(plan.Services, planned)
 	}
 
-	// Check if ports are available
-	fmt.Println("Checking if ports are available...")
-	for serviceName, serviceConfig := range composeConfig.Services {
-		for _, portMapping := range serviceConfig.Ports {
-			hostPort := strings.Split(strings.Split(portMapping, ":")[0], "-")[0]
-			if !isPortAvailable(hostPort) {
-				fmt.Printf("Warning: Port %s required by service '%s' is already in use\n", hostPort, serviceName)
+	volumeNames := make([]string, 0, len(compose.Volumes))
+	for key := range compose.Volumes {
+		volumeNames = append(volumeNames, key)
+	}
+	sort.Strings(volumeNames)
+	for _, key := range volumeNames {
+		volume := compose.Volumes[key]
+		name := volume.Name
+		if name == "" {
+			name = project + "_" + key
+			if volume.External {
+				name = key
 			}
 		}
+		if !volumeExists(name) {
+			plan.Volumes = append(plan.Volumes, name)
+		}
 	}
+	return plan
+}
 
-	// Create volumes if they don't exist
-	fmt.Println("Creating volumes...")
-	if len(composeConfig.Volumes) > 0 {
-		for volumeName := range composeConfig.Volumes {
-			if !volumeExists(volumeName) {
-				fmt.Printf("Creating volume '%s'...\n", volumeName)
-				cmd := exec.Command("docker", "volume", "create", volumeName)
-				cmd.Stdout = os.Stdout
-				cmd.Stderr = os.Stderr
-				err := cmd.Run()
-				if err != nil {
-					return fmt.Errorf("failed to create volume '%s': %v", volumeName, err)
+func printImportPlan(plan importPlan) {
+	fmt.Printf("\n📋 Import plan (compose project '%s')\n", plan.Project)
+	fmt.Println("Services to create:")
+	for _, service := range plan.Services {
+		ports := ""
+		if len(service.Ports) > 0 {
+			ports = " ports " + strings.Join(service.Ports, ", ")
+		}
+		fmt.Printf("  • %s → container %s (%s)%s\n", service.Name, service.ContainerName, service.Image, ports)
+		if reason, ok := plan.Unmanaged[service.Name]; ok {
+			fmt.Printf("    ⚠️  uses %s and cannot join ContainDB-Network\n", reason)
+		}
+	}
+	if len(plan.Volumes) > 0 {
+		fmt.Println("Volumes to create:")
+		for _, volume := range plan.Volumes {
+			fmt.Println("  •", volume)
+		}
+	}
+	if len(plan.PortConflicts) > 0 {
+		fmt.Println("Port conflicts:")
+		for _, conflict := range plan.PortConflicts {
+			fmt.Println("  ⚠️ ", conflict)
+		}
+	}
+	if len(plan.NameConflicts) > 0 {
+		fmt.Println("Container name conflicts:")
+		for _, conflict := range plan.NameConflicts {
+			fmt.Println("  ❌", conflict)
+		}
+	}
+	fmt.Println()
+}
+
+// rewriteComposeDocument applies the plan to the generic form of the compose
+// file: fixed container names, the managed label, ContainDB-Network and the
+// remapped host ports
+func rewriteComposeDocument(document yamlMap, plan importPlan) {
+	services, _ := document["services"].(yamlMap)
+	for key, value := range services {
+		name := fmt.Sprint(key)
+		service, ok := value.(yamlMap)
+		if !ok {
+			service = yamlMap{}
+			services[key] = service
+		}
+
+		if service["container_name"] == nil {
+			service["container_name"] = name
+		}
+
+		switch labels := service["labels"].(type) {
+		case []interface{}:
+			service["labels"] = append(labels, ManagedLabel+"=true")
+		case yamlMap:
+			labels[ManagedLabel] = "true"
+		default:
+			service["labels"] = yamlMap{ManagedLabel: "true"}
+		}
+
+		if _, unmanaged := plan.Unmanaged[name]; !unmanaged {
+			switch networks := service["networks"].(type) {
+			case []interface{}:
+				if !containsEntry(networks, "ContainDB-Network") {
+					service["networks"] = append(networks, "ContainDB-Network")
 				}
-			} else {
-				fmt.Printf("Volume '%s' already exists\n", volumeName)
+			case yamlMap:
+				networks["ContainDB-Network"] = nil
+			default:
+				// Keep the project's default network for the links between its services
+				service["networks"] = []interface{}{"default", "ContainDB-Network"}
+			}
+		}
+
+		if ports, ok := service["ports"].([]interface{}); ok {
+			for i, entry := range ports {
+				ports[i] = remapPortEntry(entry, name, plan.Remaps)
 			}
 		}
 	}
 
-	// Start services using docker-compose up -d
-	fmt.Println("Starting services...")
-	cmd := exec.Command("docker", "compose", "-f", composeFilePath, "up", "-d")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	networks, ok := document["networks"].(yamlMap)
+	if !ok {
+		networks = yamlMap{}
+		document["networks"] = networks
+	}
+	networks["ContainDB-Network"] = yamlMap{"external": true}
+
+	volumes, _ := document["volumes"].(yamlMap)
+	for key, value := range volumes {
+		volume, ok := value.(yamlMap)
+		if !ok {
+			volume = yamlMap{}
+			volumes[key] = volume
+		}
+		if external, _ := volume["external"].(bool); external {
+			continue
+		}
+		labels, ok := volume["labels"].(yamlMap)
+		if !ok {
+			labels = yamlMap{}
+			volume["labels"] = labels
+		}
+		labels[ManagedLabel] = "true"
+	}
+}
+
+// yamlMap is a YAML mapping decoded without a schema. Unlike yaml.MapSlice it
+// keeps the keys merged in with `<<: *anchor`.
+type yamlMap = map[interface{}]interface{}
+
+// remapPortEntry returns a ports entry (short or long syntax) with its host
+// port replaced when the plan remaps it
+func remapPortEntry(entry interface{}, service string, remaps map[string]string) interface{} {
+	switch value := entry.(type) {
+	case string:
+		port, err := ParseComposePort(value)
+		if free, ok := remaps[service+"/"+port.HostPort]; ok && err == nil {
+			port.HostPort = free
+			return port.String()
+		}
+	case yamlMap:
+		if free, ok := remaps[service+"/"+fmt.Sprint(value["published"])]; ok {
+			value["published"] = free
+		}
+	}
+	return entry
+}
+
+// containsEntry reports whether a YAML list holds the given scalar
+func containsEntry(list []interface{}, value string) bool {
+	for _, entry := range list {
+		if fmt.Sprint(entry) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// composeProjectName returns the project name Compose derives from a directory
+func composeProjectName(dir string) string {
+	name := regexp.MustCompile(`[^a-z0-9_-]`).ReplaceAllString(strings.ToLower(filepath.Base(dir)), "")
+	return strings.TrimLeft(name, "_-")
+}
+
+// containerProject returns the compose project a container belongs to, if any
+func containerProject(name string) string {
+	out, err := Command("inspect", "--format", `{{index .Config.Labels "com.docker.compose.project"}}`, name).Output()
 	if err != nil {
-		return fmt.Errorf("failed to start services: %v", err)
+		return ""
 	}
+	return strings.TrimSpace(string(out))
+}
 
-	return nil
+// nextFreePort returns the first available host port above port that no
+// other service of the import claimed
+func nextFreePort(port string, taken map[string]bool) string {
+	start, _ := strconv.Atoi(port)
+	if start < 1024 {
+		start = 1024
+	}
+	for candidate := start + 1; candidate <= 65535; candidate++ {
+		value := strconv.Itoa(candidate)
+		if taken[value] {
+			continue
+		}
+		free, err := probePort(value)
+		if err != nil {
+			// Every other port would fail the same way
+			fmt.Printf("⚠️  %v; keeping host port %s\n", err, port)
+			return port
+		}
+		if free {
+			return value
+		}
+	}
+	return port
 }
 
 // Helper function to check if a volume exists
 func volumeExists(name string) bool {
-	cmd := exec.Command("docker", "volume", "inspect", name)
+	cmd := Command("volume", "inspect", name)
 	err := cmd.Run()
 	return err == nil
 }
 
-// getRunningContainers returns a list of running Docker containers
-func getRunningContainers() ([]string, error) {
-	cmd := exec.Command("docker", "ps", "--format", "{{.Names}}")
+// getAllContainers returns the names of all containers, running or not
+func getAllContainers() (map[string]bool, error) {
+	cmd := Command("ps", "-a", "--format", "{{.Names}}")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
-	return strings.Split(string(output), "\n"), nil
+	names := make(map[string]bool)
+	for _, name := range strings.Fields(string(output)) {
+		names[name] = true
+	}
+	return names, nil
 }
 
-// isPortAvailable checks if a port is available
-func isPortAvailable(port string) bool {
+// probePort reports whether a port is free on the machine running the
+// containers, which for a remote engine is checked from a container there.
+// An error means the answer is unknown.
+func probePort(port string) (bool, error) {
+	if IsRemoteEngine() {
+		out, err := runOnEngine(fmt.Sprintf("nc -z 127.0.0.1 %s && echo busy || echo free", port))
+		if err != nil {
+			return false, fmt.Errorf("could not probe port %s on %s: %v", port, EngineHost(), err)
+		}
+		return strings.HasSuffix(out, "free"), nil
+	}
 	ln, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		return false
+		return false, nil
 	}
 	ln.Close()
-	return true
+	return true, nil
 }
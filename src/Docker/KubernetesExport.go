@@ -0,0 +1,507 @@
+package Docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// managementTools are the containers of the web UIs and the HTTPS gateway
+// ContainDB installs; they are stateless and become Deployments instead of
+// StatefulSets
+var managementTools = map[string]bool{
+	"phpmyadmin": true, "pgadmin": true, "adminer": true, "redisinsight": true,
+	"mongo-express": true, "kibana-container": true, "attu-container": true,
+	"opensearch-dashboards-container": true, "containdb-gateway": true,
+}
+
+// fileEngines are the engines served by a web UI over a database file on the
+// host; the container is a tool, the file is not its data
+var fileEngines = map[string]bool{"sqlite": true, "duckdb": true}
+
+// defaultStorageSize is the PVC size requested for each data directory
+const defaultStorageSize = "10Gi"
+
+// k8sInstance is one container translated to Kubernetes terms. It is the
+// per-instance entry of the Helm chart's values.yaml and the source of the
+// plain manifests.
+type k8sInstance struct {
+	Kind    string            `yaml:"kind"` // database or tool
+	Image   string            `yaml:"image"`
+	Command []string          `yaml:"command,omitempty"` // overrides the image's entrypoint
+	Args    []string          `yaml:"args,omitempty"`
+	Ports   []k8sPort         `yaml:"ports,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	Secrets map[string]string `yaml:"secrets,omitempty"` // credentials, stored in a Secret
+	Storage []k8sStorage      `yaml:"storage,omitempty"`
+}
+
+type k8sPort struct {
+	Name     string `yaml:"name"`
+	Port     int    `yaml:"port"`
+	Protocol string `yaml:"protocol"`
+}
+
+type k8sStorage struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	Size      string `yaml:"size"`
+}
+
+// Kubernetes objects, limited to the fields the export writes
+type k8sObject struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sMetadata       `yaml:"metadata"`
+	Type       string            `yaml:"type,omitempty"`
+	StringData map[string]string `yaml:"stringData,omitempty"`
+	Spec       interface{}       `yaml:"spec,omitempty"`
+}
+
+type k8sMetadata struct {
+	Name   string            `yaml:"name,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type k8sServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []k8sServicePort  `yaml:"ports"`
+}
+
+type k8sServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort int    `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
+
+type k8sWorkloadSpec struct {
+	ServiceName          string           `yaml:"serviceName,omitempty"`
+	Replicas             int              `yaml:"replicas"`
+	Selector             k8sLabelSelector `yaml:"selector"`
+	Template             k8sPodTemplate   `yaml:"template"`
+	VolumeClaimTemplates []k8sClaim       `yaml:"volumeClaimTemplates,omitempty"`
+}
+
+type k8sLabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type k8sPodTemplate struct {
+	Metadata k8sMetadata `yaml:"metadata"`
+	Spec     k8sPodSpec  `yaml:"spec"`
+}
+
+type k8sPodSpec struct {
+	Containers []k8sContainer `yaml:"containers"`
+}
+
+type k8sContainer struct {
+	Name         string             `yaml:"name"`
+	Image        string             `yaml:"image"`
+	Command      []string           `yaml:"command,omitempty"`
+	Args         []string           `yaml:"args,omitempty"`
+	Ports        []k8sContainerPort `yaml:"ports,omitempty"`
+	Env          []k8sEnvVar        `yaml:"env,omitempty"`
+	VolumeMounts []k8sVolumeMount   `yaml:"volumeMounts,omitempty"`
+}
+
+type k8sContainerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol"`
+}
+
+type k8sEnvVar struct {
+	Name      string        `yaml:"name"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *k8sEnvSource `yaml:"valueFrom,omitempty"`
+}
+
+type k8sEnvSource struct {
+	SecretKeyRef k8sSecretKeyRef `yaml:"secretKeyRef"`
+}
+
+type k8sSecretKeyRef struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+type k8sVolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+}
+
+type k8sClaim struct {
+	Metadata k8sMetadata  `yaml:"metadata"`
+	Spec     k8sClaimSpec `yaml:"spec"`
+}
+
+type k8sClaimSpec struct {
+	AccessModes []string           `yaml:"accessModes"`
+	Resources   k8sResourceRequest `yaml:"resources"`
+}
+
+type k8sResourceRequest struct {
+	Requests map[string]string `yaml:"requests"`
+}
+
+// writeKubernetesExport writes the containers as Kubernetes manifests (format
+// "k8s", one multi-document file) or as a Helm chart (format "helm", a directory)
+func writeKubernetesExport(infos []ContainerInfo, format, path string) error {
+	instances := make(map[string]k8sInstance)
+	for _, info := range infos {
+		instances[k8sName(info.Name)] = newK8sInstance(info)
+	}
+
+	if format == "helm" {
+		return writeHelmChart(path, instances)
+	}
+
+	manifests, err := generateKubernetesManifests(instances)
+	if err != nil {
+		return err
+	}
+	// The Secrets hold the credentials in plain text
+	return os.WriteFile(path, []byte(manifests), 0600)
+}
+
+// newK8sInstance translates the inspected settings of a container
+func newK8sInstance(info ContainerInfo) k8sInstance {
+	instance := k8sInstance{Kind: "database", Image: info.Image, Command: info.Entrypoint, Args: info.Command}
+	if managementTools[info.Name] || fileEngines[engineOfImage(info.Image)] {
+		instance.Kind = "tool"
+	}
+
+	// Every container port is served in the cluster, published on the host or
+	// not, since other pods reach it by name like ContainDB-Network does
+	containerPorts := append([]string{}, info.ExposedPorts...)
+	for _, mapping := range info.Ports {
+		containerPorts = append(containerPorts, mapping.ContainerPort+"/"+mapping.Protocol)
+	}
+	seenPorts := make(map[string]bool)
+	for _, portProto := range containerPorts {
+		number, protocol, _ := strings.Cut(portProto, "/")
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		port, err := strconv.Atoi(number)
+		name := fmt.Sprintf("%s-%d", protocol, port)
+		if err != nil || seenPorts[name] {
+			continue
+		}
+		seenPorts[name] = true
+		instance.Ports = append(instance.Ports, k8sPort{Name: name, Port: port, Protocol: strings.ToUpper(protocol)})
+	}
+	sort.Slice(instance.Ports, func(i, j int) bool {
+		if instance.Ports[i].Port != instance.Ports[j].Port {
+			return instance.Ports[i].Port < instance.Ports[j].Port
+		}
+		return instance.Ports[i].Protocol < instance.Ports[j].Protocol
+	})
+
+	for _, envVar := range info.EnvVars {
+		key, value, _ := strings.Cut(envVar, "=")
+		if isCredentialEnv(key) || isSecretEnv(engineOfImage(info.Image), key, value) {
+			if instance.Secrets == nil {
+				instance.Secrets = make(map[string]string)
+			}
+			instance.Secrets[key] = value
+		} else {
+			if instance.Env == nil {
+				instance.Env = make(map[string]string)
+			}
+			instance.Env[key] = value
+		}
+	}
+
+	// Management tools keep nothing worth persisting; databases get a claim per data directory
+	if instance.Kind == "database" {
+		for i, volume := range info.Volumes {
+			name := "data"
+			if i > 0 {
+				name = fmt.Sprintf("data-%d", i)
+			}
+			instance.Storage = append(instance.Storage, k8sStorage{Name: name, MountPath: volume.Destination, Size: defaultStorageSize})
+		}
+	}
+	return instance
+}
+
+// credentialEnvPattern matches environment variables holding credentials
+var credentialEnvPattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|_PASS$|SECRET|TOKEN|API_?KEY|_USER$|_USERNAME$|CREDENTIAL)`)
+
+func isCredentialEnv(key string) bool {
+	return credentialEnvPattern.MatchString(key)
+}
+
+// k8sName turns a container name into a valid Kubernetes object name (RFC 1123 label)
+func k8sName(name string) string {
+	name = regexp.MustCompile(`[^a-z0-9-]+`).ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// generateKubernetesManifests renders a StatefulSet (database) or Deployment
+// (tool) per instance, with its Service and credentials Secret, as one
+// multi-document YAML
+func generateKubernetesManifests(instances map[string]k8sInstance) (string, error) {
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var documents []string
+	for _, name := range names {
+		for _, object := range k8sObjects(name, instances[name]) {
+			data, err := yaml.Marshal(object)
+			if err != nil {
+				return "", err
+			}
+			documents = append(documents, string(data))
+		}
+	}
+	return "---\n" + strings.Join(documents, "---\n"), nil
+}
+
+// k8sObjects returns the Kubernetes objects of one instance
+func k8sObjects(name string, instance k8sInstance) []k8sObject {
+	labels := map[string]string{"app.kubernetes.io/name": name, "app.kubernetes.io/managed-by": "containdb"}
+	selector := map[string]string{"app.kubernetes.io/name": name}
+	secretName := name + "-credentials"
+	var objects []k8sObject
+
+	if len(instance.Secrets) > 0 {
+		objects = append(objects, k8sObject{
+			APIVersion: "v1", Kind: "Secret",
+			Metadata:   k8sMetadata{Name: secretName, Labels: labels},
+			Type:       "Opaque",
+			StringData: instance.Secrets,
+		})
+	}
+
+	if len(instance.Ports) > 0 {
+		spec := k8sServiceSpec{Selector: selector}
+		for _, port := range instance.Ports {
+			spec.Ports = append(spec.Ports, k8sServicePort{Name: port.Name, Port: port.Port, TargetPort: port.Port, Protocol: port.Protocol})
+		}
+		objects = append(objects, k8sObject{APIVersion: "v1", Kind: "Service", Metadata: k8sMetadata{Name: name, Labels: labels}, Spec: spec})
+	}
+
+	container := k8sContainer{Name: name, Image: instance.Image, Command: instance.Command, Args: instance.Args}
+	for _, port := range instance.Ports {
+		container.Ports = append(container.Ports, k8sContainerPort{Name: port.Name, ContainerPort: port.Port, Protocol: port.Protocol})
+	}
+	for _, key := range sortedKeys(instance.Env) {
+		container.Env = append(container.Env, k8sEnvVar{Name: key, Value: instance.Env[key]})
+	}
+	for _, key := range sortedKeys(instance.Secrets) {
+		container.Env = append(container.Env, k8sEnvVar{Name: key, ValueFrom: &k8sEnvSource{SecretKeyRef: k8sSecretKeyRef{Name: secretName, Key: key}}})
+	}
+
+	spec := k8sWorkloadSpec{
+		Replicas: 1,
+		Selector: k8sLabelSelector{MatchLabels: selector},
+		Template: k8sPodTemplate{Metadata: k8sMetadata{Labels: labels}},
+	}
+	kind := "Deployment"
+	if instance.Kind == "database" {
+		kind = "StatefulSet"
+		spec.ServiceName = name
+		for _, storage := range instance.Storage {
+			container.VolumeMounts = append(container.VolumeMounts, k8sVolumeMount{Name: storage.Name, MountPath: storage.MountPath})
+			spec.VolumeClaimTemplates = append(spec.VolumeClaimTemplates, k8sClaim{
+				Metadata: k8sMetadata{Name: storage.Name},
+				Spec: k8sClaimSpec{
+					AccessModes: []string{"ReadWriteOnce"},
+					Resources:   k8sResourceRequest{Requests: map[string]string{"storage": storage.Size}},
+				},
+			})
+		}
+	}
+	spec.Template.Spec.Containers = []k8sContainer{container}
+	objects = append(objects, k8sObject{APIVersion: "apps/v1", Kind: kind, Metadata: k8sMetadata{Name: name, Labels: labels}, Spec: spec})
+	return objects
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeHelmChart writes a chart whose values.yaml holds one entry per instance
+// and whose templates render the same objects as generateKubernetesManifests
+func writeHelmChart(chartDir string, instances map[string]k8sInstance) error {
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0755); err != nil {
+		return err
+	}
+
+	values, err := yaml.Marshal(map[string]interface{}{"instances": instances})
+	if err != nil {
+		return err
+	}
+	files := map[string]string{
+		"Chart.yaml":               helmChartFile,
+		"values.yaml":              "# One entry per ContainDB container; secrets end up in a Kubernetes Secret\n" + string(values),
+		"templates/secrets.yaml":   helmSecretsTemplate,
+		"templates/services.yaml":  helmServicesTemplate,
+		"templates/workloads.yaml": helmWorkloadsTemplate,
+		"templates/_helpers.tpl":   helmHelpersTemplate,
+		".helmignore":              ".git/\n*.swp\n",
+	}
+	for name, content := range files {
+		mode := os.FileMode(0644)
+		if name == "values.yaml" {
+			// values.yaml holds the credentials in plain text
+			mode = 0600
+		}
+		if err := os.WriteFile(filepath.Join(chartDir, name), []byte(content), mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const helmChartFile = `apiVersion: v2
+name: containdb
+description: Databases and tools exported from ContainDB
+type: application
+version: 0.1.0
+`
+
+const helmHelpersTemplate = `{{- define "containdb.labels" -}}
+app.kubernetes.io/name: {{ .name }}
+app.kubernetes.io/instance: {{ .release }}
+app.kubernetes.io/managed-by: {{ .service }}
+{{- end }}
+`
+
+const helmSecretsTemplate = `{{- range $name, $instance := .Values.instances }}
+{{- if $instance.secrets }}
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ $name }}-credentials
+  labels:
+    {{- include "containdb.labels" (dict "name" $name "release" $.Release.Name "service" $.Release.Service) | nindent 4 }}
+type: Opaque
+stringData:
+  {{- range $key, $value := $instance.secrets }}
+  {{ $key }}: {{ $value | quote }}
+  {{- end }}
+{{- end }}
+{{- end }}
+`
+
+const helmServicesTemplate = `{{- range $name, $instance := .Values.instances }}
+{{- if $instance.ports }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ $name }}
+  labels:
+    {{- include "containdb.labels" (dict "name" $name "release" $.Release.Name "service" $.Release.Service) | nindent 4 }}
+spec:
+  selector:
+    app.kubernetes.io/name: {{ $name }}
+  ports:
+    {{- range $instance.ports }}
+    - name: {{ .name }}
+      port: {{ .port }}
+      targetPort: {{ .port }}
+      protocol: {{ .protocol }}
+    {{- end }}
+{{- end }}
+{{- end }}
+`
+
+const helmWorkloadsTemplate = `{{- range $name, $instance := .Values.instances }}
+{{- $database := eq $instance.kind "database" }}
+---
+apiVersion: apps/v1
+kind: {{ if $database }}StatefulSet{{ else }}Deployment{{ end }}
+metadata:
+  name: {{ $name }}
+  labels:
+    {{- include "containdb.labels" (dict "name" $name "release" $.Release.Name "service" $.Release.Service) | nindent 4 }}
+spec:
+  {{- if $database }}
+  serviceName: {{ $name }}
+  {{- end }}
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: {{ $name }}
+  template:
+    metadata:
+      labels:
+        {{- include "containdb.labels" (dict "name" $name "release" $.Release.Name "service" $.Release.Service) | nindent 8 }}
+    spec:
+      containers:
+        - name: {{ $name }}
+          image: {{ $instance.image }}
+          {{- with $instance.command }}
+          command:
+            {{- toYaml . | nindent 12 }}
+          {{- end }}
+          {{- with $instance.args }}
+          args:
+            {{- toYaml . | nindent 12 }}
+          {{- end }}
+          {{- with $instance.ports }}
+          ports:
+            {{- range . }}
+            - name: {{ .name }}
+              containerPort: {{ .port }}
+              protocol: {{ .protocol }}
+            {{- end }}
+          {{- end }}
+          {{- if or $instance.env $instance.secrets }}
+          env:
+            {{- range $key, $value := $instance.env }}
+            - name: {{ $key }}
+              value: {{ $value | quote }}
+            {{- end }}
+            {{- range $key, $value := $instance.secrets }}
+            - name: {{ $key }}
+              valueFrom:
+                secretKeyRef:
+                  name: {{ $name }}-credentials
+                  key: {{ $key }}
+            {{- end }}
+          {{- end }}
+          {{- if $database }}
+          {{- with $instance.storage }}
+          volumeMounts:
+            {{- range . }}
+            - name: {{ .name }}
+              mountPath: {{ .mountPath }}
+            {{- end }}
+          {{- end }}
+          {{- end }}
+  {{- if and $database $instance.storage }}
+  volumeClaimTemplates:
+    {{- range $instance.storage }}
+    - metadata:
+        name: {{ .name }}
+      spec:
+        accessModes: ["ReadWriteOnce"]
+        resources:
+          requests:
+            storage: {{ .size }}
+    {{- end }}
+  {{- end }}
+{{- end }}
+`
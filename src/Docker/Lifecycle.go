@@ -0,0 +1,95 @@
+package Docker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ContainerState describes a ContainDB container and its current state
+type ContainerState struct {
+	Name   string
+	State  string // running, exited, paused, created, restarting or dead
+	Status string // human readable status, e.g. "Up 2 hours"
+}
+
+// LifecycleActions are the docker commands that change a container's state
+// without removing it
+var LifecycleActions = []string{"stop", "start", "restart", "pause", "unpause"}
+
+// ListContainDBInstances returns every container on ContainDB-Network,
+// including stopped and paused ones
+func ListContainDBInstances() ([]ContainerState, error) {
+	cmd := Command("ps", "-a",
+		"--filter", "network=ContainDB-Network",
+		"--format", "{{.Names}}\t{{.State}}\t{{.Status}}")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []ContainerState
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		instances = append(instances, ContainerState{Name: parts[0], State: parts[1], Status: parts[2]})
+	}
+	return instances, nil
+}
+
+// GetContainDBInstance returns the state of the named ContainDB container, or an
+// error when no container with that name is attached to ContainDB-Network
+func GetContainDBInstance(name string) (ContainerState, error) {
+	instances, err := ListContainDBInstances()
+	if err != nil {
+		return ContainerState{}, fmt.Errorf("failed to list containers: %v", err)
+	}
+	for _, instance := range instances {
+		if instance.Name == name {
+			return instance, nil
+		}
+	}
+	return ContainerState{}, fmt.Errorf("'%s' is not a ContainDB container", name)
+}
+
+// AvailableActions returns the lifecycle actions that make sense for a container
+// in the given state
+func AvailableActions(state string) []string {
+	switch state {
+	case "running", "restarting":
+		return []string{"stop", "restart", "pause"}
+	case "paused":
+		return []string{"unpause", "stop"}
+	default: // exited, created, dead
+		return []string{"start"}
+	}
+}
+
+// ContainerLifecycle runs a lifecycle action (stop, start, restart, pause, unpause)
+// on a ContainDB container. The container keeps its configuration, volumes and
+// port mappings.
+func ContainerLifecycle(action, name string) error {
+	valid := false
+	for _, a := range LifecycleActions {
+		if a == action {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("unknown action '%s' (expected one of: %s)", action, strings.Join(LifecycleActions, ", "))
+	}
+
+	if _, err := GetContainDBInstance(name); err != nil {
+		return err
+	}
+
+	cmd := Command(action, name)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to %s %s: %v", action, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package Docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalCA is the certificate authority ContainDB issues instance certificates
+// with. It lives next to the config file and is created on first use.
+type LocalCA struct {
+	Cert *x509.Certificate
+	Key  *rsa.PrivateKey
+}
+
+// TLSCAEnv marks a container serving TLS and holds the CA path inside it,
+// which ContainDB's own clients verify the server with
+const TLSCAEnv = "CONTAINDB_TLS_CA"
+
+// CADir returns the directory holding the local CA
+func CADir() string {
+	return filepath.Join(filepath.Dir(ConfigPath()), "ca")
+}
+
+// CACertPath returns the PEM certificate of the local CA, the file clients trust
+func CACertPath() string {
+	return filepath.Join(CADir(), "ca.pem")
+}
+
+func caKeyPath() string {
+	return filepath.Join(CADir(), "ca-key.pem")
+}
+
+// LoadLocalCA returns the local CA, creating it when it does not exist yet
+func LoadLocalCA() (*LocalCA, error) {
+	certPEM, certErr := os.ReadFile(CACertPath())
+	keyPEM, keyErr := os.ReadFile(caKeyPath())
+	if os.IsNotExist(certErr) && os.IsNotExist(keyErr) {
+		return createLocalCA()
+	}
+	if certErr != nil {
+		return nil, certErr
+	}
+	if keyErr != nil {
+		return nil, keyErr
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, fmt.Errorf("invalid local CA in %s", CADir())
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local CA certificate: %v", err)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local CA key: %v", err)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, fmt.Errorf("the local CA expired on %s; remove %s to create a new one", cert.NotAfter.Format("2006-01-02"), CADir())
+	}
+	return &LocalCA{Cert: cert, Key: key}, nil
+}
+
+func createLocalCA() (*LocalCA, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 3072)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"ContainDB"}, CommonName: "ContainDB Local CA " + hostname},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(CADir(), 0700); err != nil {
+		return nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(caKeyPath(), keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(CACertPath(), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return nil, err
+	}
+	fmt.Printf("🔐 Created the ContainDB local CA at %s\n", CADir())
+	return &LocalCA{Cert: cert, Key: key}, nil
+}
+
+// CertPEM returns the CA certificate in PEM form
+func (ca *LocalCA) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Cert.Raw})
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the CA certificate
+func (ca *LocalCA) Fingerprint() string {
+	sum := sha256.Sum256(ca.Cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// IssueServerCert returns a PEM certificate and PKCS#8 key for a server
+// reachable at hosts, host names and IP addresses alike
+func (ca *LocalCA) IssueServerCert(commonName string, hosts []string) (certPEM, keyPEM []byte, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"ContainDB"}, CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		// Some clients reject server certificates valid for more than 825 days
+		NotAfter:    time.Now().AddDate(0, 0, 825),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	seen := make(map[string]bool)
+	for _, host := range hosts {
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, &key.PublicKey, ca.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// NetworkInterface is an IPv4 address of one of this machine's interfaces
+type NetworkInterface struct {
+	Name string
+	IP   string
+}
+
+// LocalInterfaces returns the IPv4 addresses of the interfaces that are up,
+// loopback excluded
+func LocalInterfaces() []NetworkInterface {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	var found []NetworkInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				found = append(found, NetworkInterface{Name: iface.Name, IP: ipNet.IP.String()})
+			}
+		}
+	}
+	return found
+}
+
+// ContainerFile is a file copied into a container with its owner and mode
+type ContainerFile struct {
+	Path    string // absolute path inside the container
+	Content []byte
+	Mode    int64
+	UID     int
+	GID     int
+}
+
+// containdbDir is the directory ContainDB owns inside database containers
+const containdbDir = "/etc/containdb"
+
+// CopyFilesToContainer copies files into a container as one tar archive, so
+// their owner and mode are kept. Directories under containdbDir are owned like
+// the files; other parents are left alone, `cp` creates missing ones, since an
+// archive entry would reset the mode and owner of e.g. /tmp or /etc.
+func CopyFilesToContainer(name string, files []ContainerFile) error {
+	var buffer bytes.Buffer
+	archive := tar.NewWriter(&buffer)
+	dirs := make(map[string]bool)
+	now := time.Now()
+	for _, file := range files {
+		// Owned parent directories first, so the server can list them
+		var parents []string
+		for dir := path.Dir(file.Path); isContainDBDir(dir) && !dirs[dir]; dir = path.Dir(dir) {
+			parents = append([]string{dir}, parents...)
+			dirs[dir] = true
+		}
+		for _, dir := range parents {
+			header := &tar.Header{Typeflag: tar.TypeDir, Name: strings.TrimPrefix(dir, "/") + "/", Mode: 0755,
+				Uid: file.UID, Gid: file.GID, ModTime: now}
+			if err := archive.WriteHeader(header); err != nil {
+				return err
+			}
+		}
+		header := &tar.Header{Typeflag: tar.TypeReg, Name: strings.TrimPrefix(file.Path, "/"), Mode: file.Mode,
+			Size: int64(len(file.Content)), Uid: file.UID, Gid: file.GID, ModTime: now}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := archive.Write(file.Content); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	cmd := Command("cp", "-a", "-", name+":/")
+	cmd.Stdin = &buffer
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy files into %s: %v %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func isContainDBDir(dir string) bool {
+	return dir == containdbDir || strings.HasPrefix(dir, containdbDir+"/")
+}
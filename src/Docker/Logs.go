@@ -0,0 +1,66 @@
+package Docker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// StreamContainerLogs runs `docker logs` for a container and passes every line
+// (stdout and stderr combined) to handle. With follow it blocks until the
+// container stops or the user interrupts the command.
+func StreamContainerLogs(name string, follow bool, since string, handle func(line string)) error {
+	args := []string{"logs"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	args = append(args, name)
+
+	if err := streamLines(Command(args...), handle); err != nil {
+		return fmt.Errorf("failed to read logs of %s: %v", name, err)
+	}
+	return nil
+}
+
+// StreamExec runs a command inside a container (e.g. `tail -F` on a log file)
+// and passes every line of its output to handle
+func StreamExec(name string, command []string, handle func(line string)) error {
+	args := append([]string{"exec", name}, command...)
+	if err := streamLines(Command(args...), handle); err != nil {
+		return fmt.Errorf("failed to run %s in %s: %v", command[0], name, err)
+	}
+	return nil
+}
+
+// streamLines runs cmd and calls handle for every line of its combined output
+func streamLines(cmd *exec.Cmd, handle func(line string)) error {
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		writer.Close()
+		done <- err
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	// Elasticsearch and MongoDB write long JSON lines
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+	// Keep docker from blocking on a full pipe if scanning stopped early
+	_, _ = io.Copy(io.Discard, reader)
+
+	return <-done
+}
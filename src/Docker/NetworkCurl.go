@@ -0,0 +1,36 @@
+package Docker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// NetworkCurl runs a throwaway curl container attached to ContainDB-Network and
+// returns its output. Containers are reachable by name, so this works even when
+// the target database has no host port mapping and its image ships without curl.
+func NetworkCurl(args ...string) (string, error) {
+	return NetworkCurlInput(nil, args...)
+}
+
+// NetworkCurlInput is NetworkCurl with input available to curl on stdin, for
+// request bodies read with `--data-binary @-`
+func NetworkCurlInput(input []byte, args ...string) (string, error) {
+	cmdArgs := []string{"run", "--rm"}
+	if input != nil {
+		cmdArgs = append(cmdArgs, "-i")
+	}
+	cmdArgs = append(cmdArgs, "--network", "ContainDB-Network", "curlimages/curl:latest",
+		"-sS", "--fail-with-body", "--retry", "30", "--retry-delay", "2", "--retry-connrefused")
+	cmdArgs = append(cmdArgs, args...)
+
+	cmd := Command(cmdArgs...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("request failed: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,206 @@
+package Docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// minMaxMapCount is the vm.max_map_count Elasticsearch and OpenSearch refuse to start below
+const minMaxMapCount = 262144
+
+// CheckMaxMapCount verifies the kernel setting Elasticsearch and OpenSearch
+// recommend for their memory-mapped index files. ContainDB runs them as single
+// nodes, where the bootstrap checks are not enforced, so a low value only warns.
+func CheckMaxMapCount() CheckResult {
+	result := CheckResult{Name: "vm.max_map_count"}
+	var data []byte
+	var err error
+	if IsRemoteEngine() {
+		// Containers share the kernel of the remote machine
+		var out string
+		out, err = runOnEngine("cat /proc/sys/vm/max_map_count")
+		data = []byte(out)
+	} else if !IsLinux() {
+		result.Status, result.Message = CheckPass, "managed by the Docker Desktop VM"
+		return result
+	} else {
+		data, err = os.ReadFile("/proc/sys/vm/max_map_count")
+	}
+	if err != nil {
+		result.Status, result.Message = CheckWarn, "could not read vm.max_map_count"
+		return result
+	}
+	value, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	if value < minMaxMapCount {
+		result.Status = CheckWarn
+		result.Message = fmt.Sprintf("is %d, %d is recommended for large indices", value, minMaxMapCount)
+		result.Remediation = fmt.Sprintf("Run `sudo sysctl -w vm.max_map_count=%d` on the Docker host and add `vm.max_map_count=%d` to /etc/sysctl.conf to keep it after reboot",
+			minMaxMapCount, minMaxMapCount)
+		return result
+	}
+	result.Status, result.Message = CheckPass, fmt.Sprintf("is %d", value)
+	return result
+}
+
+// CheckPort verifies that a host port is free to publish a container port on
+func CheckPort(port string) CheckResult {
+	result := CheckResult{Name: "Port " + port}
+	free, err := probePort(port)
+	if err != nil {
+		result.Status, result.Message = CheckWarn, "could not be checked"
+		result.Remediation = err.Error()
+		return result
+	}
+	if free {
+		if number, _ := strconv.Atoi(port); number > 0 && number < 1024 && IsRootless() {
+			result.Status = CheckFail
+			result.Message = fmt.Sprintf("is privileged and %s runs rootless", RuntimeName())
+			result.Remediation = fmt.Sprintf("Choose a host port above 1023, or run `sudo sysctl net.ipv4.ip_unprivileged_port_start=%d`", number)
+			return result
+		}
+		result.Status, result.Message = CheckPass, "is free"
+		return result
+	}
+
+	result.Status = CheckFail
+	result.Message = "is already in use"
+	out, _ := Command("ps", "--filter", "publish="+port, "--format", "{{.Names}}").Output()
+	if owner := strings.TrimSpace(string(out)); owner != "" {
+		result.Message = fmt.Sprintf("is already published by container %s", strings.ReplaceAll(owner, "\n", ", "))
+		result.Remediation = fmt.Sprintf("Stop it with `containDB stop %s` or choose a custom host port", strings.Split(owner, "\n")[0])
+	} else {
+		result.Remediation = "Stop the program listening on it or choose a custom host port"
+	}
+	return result
+}
+
+// ImagePullGB returns the disk space in GB pulling an image takes: 0 when it
+// is already pulled, else estimated from the registry manifest for the host's
+// architecture. known is false when the manifest could not be read.
+func ImagePullGB(image string) (gb float64, known bool) {
+	if RuntimeName() == "podman" {
+		image = QualifyImage(image)
+	}
+	if Command("image", "inspect", "--format", "{{.Size}}", image).Run() == nil {
+		return 0, true
+	}
+	out, err := Command("manifest", "inspect", "--verbose", image).Output()
+	if err != nil {
+		return 0, false
+	}
+
+	// A multi-platform image lists one entry per platform, a single one is an object
+	type layers struct {
+		Descriptor struct {
+			Platform struct {
+				Architecture string `json:"architecture"`
+				OS           string `json:"os"`
+			} `json:"platform"`
+		} `json:"Descriptor"`
+		SchemaV2Manifest struct {
+			Layers []struct {
+				Size int64 `json:"size"`
+			} `json:"layers"`
+		} `json:"SchemaV2Manifest"`
+	}
+	var entries []layers
+	if json.Unmarshal(out, &entries) != nil {
+		var single layers
+		if json.Unmarshal(out, &single) != nil {
+			return 0, false
+		}
+		entries = []layers{single}
+	}
+
+	hostArch := DockerArch()
+	for _, entry := range entries {
+		platform := entry.Descriptor.Platform
+		if len(entries) > 1 && (platform.OS != "linux" || platform.Architecture != hostArch) {
+			continue
+		}
+		var compressed int64
+		for _, layer := range entry.SchemaV2Manifest.Layers {
+			compressed += layer.Size
+		}
+		if compressed == 0 {
+			return 0, false
+		}
+		// Layers are stored gzipped; extracted they take about twice the space
+		return 2 * float64(compressed) / (1024 * 1024 * 1024), true
+	}
+	return 0, false
+}
+
+// CheckImageArch verifies that an image is published for the architecture of
+// the Docker host, e.g. that an arm64 Mac does not fall back to slow emulation
+func CheckImageArch(image string) CheckResult {
+	result := CheckResult{Name: "Image architecture"}
+	hostArch := DockerArch()
+	if RuntimeName() == "podman" {
+		image = QualifyImage(image)
+	}
+
+	var archs []string
+	if out, err := Command("image", "inspect", "--format", "{{.Architecture}}", image).Output(); err == nil {
+		archs = []string{strings.TrimSpace(string(out))}
+	} else if out, err := Command("manifest", "inspect", image).Output(); err == nil {
+		var manifest struct {
+			Manifests []struct {
+				Platform struct {
+					Architecture string `json:"architecture"`
+					OS           string `json:"os"`
+				} `json:"platform"`
+			} `json:"manifests"`
+		}
+		if json.Unmarshal(out, &manifest) == nil {
+			for _, m := range manifest.Manifests {
+				if m.Platform.OS == "linux" {
+					archs = append(archs, m.Platform.Architecture)
+				}
+			}
+		}
+		if len(archs) == 0 {
+			// A single-platform manifest does not name its architecture; almost always amd64
+			archs = []string{"amd64"}
+		}
+	} else {
+		result.Status, result.Message = CheckWarn, fmt.Sprintf("could not read the manifest of %s", image)
+		return result
+	}
+
+	for _, arch := range archs {
+		if arch == hostArch {
+			result.Status, result.Message = CheckPass, fmt.Sprintf("%s supports %s", image, hostArch)
+			return result
+		}
+	}
+	result.Status = CheckWarn
+	result.Message = fmt.Sprintf("%s is published for %s only, the host is %s", image, strings.Join(archs, ", "), hostArch)
+	result.Remediation = "The container runs under emulation, expect it to be slow or crash; enable Rosetta in Docker Desktop on Apple Silicon"
+	return result
+}
+
+// DockerArch returns the architecture of the Docker host in image platform
+// notation (amd64, arm64, ...)
+func DockerArch() string {
+	arch, err := runtimeInfo("arch")
+	if err != nil {
+		return runtime.GOARCH
+	}
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	case "armv7l":
+		return "arm"
+	case "":
+		return runtime.GOARCH
+	default:
+		return arch
+	}
+}
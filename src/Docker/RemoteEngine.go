@@ -0,0 +1,141 @@
+package Docker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// engineHost caches the address published ports are reachable at, see EngineHost
+var engineHost string
+
+// UseEngine points every runtime command at a remote engine. host is the name
+// of a saved host or a DOCKER_HOST URL (tcp://, ssh://); context is a Docker
+// context (a Podman connection with --runtime podman). Without either, the
+// default host of the config is used, if any.
+func UseEngine(host, context string) error {
+	if host != "" && context != "" {
+		return fmt.Errorf("--host and --context cannot be used together")
+	}
+	if host == "" && context == "" && os.Getenv("DOCKER_HOST") == "" && os.Getenv("DOCKER_CONTEXT") == "" {
+		if config, err := LoadConfig(); err == nil {
+			host = config.DefaultHost
+		}
+	}
+
+	if context != "" {
+		switch RuntimeName() {
+		case "docker":
+			os.Setenv("DOCKER_CONTEXT", context)
+		case "podman":
+			os.Setenv("CONTAINER_CONNECTION", context)
+		default:
+			return fmt.Errorf("%s does not support contexts, use --host instead", RuntimeName())
+		}
+		return nil
+	}
+	if host == "" {
+		return nil
+	}
+
+	saved := SavedHost{URL: host}
+	if !strings.Contains(host, "://") {
+		config, err := LoadConfig()
+		if err != nil {
+			return err
+		}
+		found, ok := config.FindHost(host)
+		if !ok {
+			return fmt.Errorf("unknown host '%s', add it with `containdb host add %s <url>`", host, host)
+		}
+		saved = found
+	}
+
+	switch RuntimeName() {
+	case "docker":
+		os.Setenv("DOCKER_HOST", saved.URL)
+		if saved.TLSVerify {
+			os.Setenv("DOCKER_TLS_VERIFY", "1")
+		}
+		if saved.CertPath != "" {
+			os.Setenv("DOCKER_CERT_PATH", saved.CertPath)
+		}
+	case "podman":
+		os.Setenv("CONTAINER_HOST", saved.URL)
+	default:
+		return fmt.Errorf("%s cannot manage a remote engine", RuntimeName())
+	}
+	return nil
+}
+
+// EngineHost returns the address ports published by containers are reachable
+// at: "localhost" for a local engine, the remote machine's host name otherwise
+func EngineHost() string {
+	if engineHost == "" {
+		engineHost = "localhost"
+		if host := hostFromEndpoint(engineEndpoint()); host != "" {
+			engineHost = host
+		}
+	}
+	return engineHost
+}
+
+// IsRemoteEngine reports whether containers run on another machine
+func IsRemoteEngine() bool {
+	return EngineHost() != "localhost"
+}
+
+// engineEndpoint returns the URL of the engine the runtime talks to
+func engineEndpoint() string {
+	switch RuntimeName() {
+	case "docker":
+		if host := os.Getenv("DOCKER_HOST"); host != "" {
+			return host
+		}
+		// Honors DOCKER_CONTEXT and the context selected with `docker context use`
+		out, err := Command("context", "inspect", "--format", "{{.Endpoints.docker.Host}}").Output()
+		if err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	case "podman":
+		if host := os.Getenv("CONTAINER_HOST"); host != "" {
+			return host
+		}
+		if connection := os.Getenv("CONTAINER_CONNECTION"); connection != "" {
+			out, _ := Command("system", "connection", "list", "--format", "{{.Name}} {{.URI}}").Output()
+			for _, line := range strings.Split(string(out), "\n") {
+				if fields := strings.Fields(line); len(fields) == 2 && fields[0] == connection {
+					return fields[1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// hostFromEndpoint extracts the machine of a tcp://, ssh:// or http(s):// engine
+// URL; local sockets and pipes return ""
+func hostFromEndpoint(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	switch parsed.Scheme {
+	case "tcp", "ssh", "http", "https":
+		host := parsed.Hostname()
+		if host == "127.0.0.1" || host == "::1" {
+			return "localhost"
+		}
+		return host
+	}
+	return ""
+}
+
+// runOnEngine runs a shell command in a throwaway container sharing the engine
+// host's network, to inspect a remote machine (listening ports, kernel settings,
+// free disk) the way a local check would inspect this one
+func runOnEngine(script string) (string, error) {
+	out, err := Command("run", "--rm", "--network", "host", "alpine:latest", "sh", "-c", script).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
@@ -0,0 +1,153 @@
+package Docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Runtimes are the container CLIs ContainDB can drive. They share Docker's
+// command line, so only image names, `info`/`version` formats and compose differ.
+var Runtimes = []string{"docker", "podman", "nerdctl"}
+
+// runtimeBinary is the container CLI every command runs with, see SetRuntime
+var runtimeBinary = "docker"
+
+// SetRuntime selects the container CLI. An empty name auto-detects it from
+// CONTAINDB_RUNTIME or the first of Runtimes found on the PATH.
+func SetRuntime(name string) error {
+	if name == "" {
+		name = os.Getenv("CONTAINDB_RUNTIME")
+	}
+	if name == "" {
+		for _, candidate := range Runtimes {
+			if _, err := exec.LookPath(candidate); err == nil {
+				name = candidate
+				break
+			}
+		}
+	}
+	if name == "" {
+		// Nothing installed yet; DockerStarter offers to install Docker
+		runtimeBinary = "docker"
+		return nil
+	}
+
+	valid := false
+	for _, runtime := range Runtimes {
+		if runtime == name {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("unknown runtime '%s' (expected one of: %s)", name, strings.Join(Runtimes, ", "))
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("runtime '%s' is not installed", name)
+	}
+	runtimeBinary = name
+	return nil
+}
+
+// RuntimeName returns the container CLI in use: docker, podman or nerdctl
+func RuntimeName() string {
+	return runtimeBinary
+}
+
+// Command returns a command running the selected container CLI with args.
+// Podman resolves short image names against several registries and refuses to
+// guess without a TTY, so images are qualified with docker.io for it.
+func Command(args ...string) *exec.Cmd {
+	if runtimeBinary == "podman" {
+		args = qualifyImageArgs(args)
+	}
+	return exec.Command(runtimeBinary, args...)
+}
+
+// ComposeCommand returns a command running Compose for the selected runtime
+func ComposeCommand(args ...string) *exec.Cmd {
+	if _, err := exec.LookPath(runtimeBinary + "-compose"); err == nil && exec.Command(runtimeBinary, "compose", "version").Run() != nil {
+		// Standalone docker-compose / podman-compose without the compose subcommand
+		return exec.Command(runtimeBinary+"-compose", args...)
+	}
+	return exec.Command(runtimeBinary, append([]string{"compose"}, args...)...)
+}
+
+// infoFormats are the `info` templates of each runtime for the values ContainDB reads
+var infoFormats = map[string]map[string]string{
+	"docker":  {"memtotal": "{{.MemTotal}}", "rootdir": "{{.DockerRootDir}}", "arch": "{{.Architecture}}", "rootless": "{{.SecurityOptions}}"},
+	"nerdctl": {"memtotal": "{{.MemTotal}}", "rootdir": "{{.DockerRootDir}}", "arch": "{{.Architecture}}", "rootless": "{{.SecurityOptions}}"},
+	"podman":  {"memtotal": "{{.Host.MemTotal}}", "rootdir": "{{.Store.GraphRoot}}", "arch": "{{.Host.Arch}}", "rootless": "{{.Host.Security.Rootless}}"},
+}
+
+// runtimeInfo returns one value of `<runtime> info` (memtotal, rootdir, arch or rootless)
+func runtimeInfo(key string) (string, error) {
+	out, err := exec.Command(runtimeBinary, "info", "--format", infoFormats[runtimeBinary][key]).Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// IsRootless reports whether the runtime runs containers without root, which
+// cannot publish ports below net.ipv4.ip_unprivileged_port_start
+func IsRootless() bool {
+	value, err := runtimeInfo("rootless")
+	if err != nil {
+		return false
+	}
+	return value == "true" || strings.Contains(value, "rootless")
+}
+
+// versionArgs returns the arguments that print the engine version, which also
+// tells whether the runtime is usable
+func versionArgs() []string {
+	switch runtimeBinary {
+	case "podman":
+		// Local podman has no server part, the client is the engine
+		return []string{"version", "--format", "{{.Client.Version}}"}
+	case "nerdctl":
+		return []string{"info", "--format", "{{.ServerVersion}}"}
+	}
+	return []string{"version", "--format", "{{.Server.Version}}"}
+}
+
+// booleanRunFlags are the run/create flags ContainDB uses that take no value
+var booleanRunFlags = map[string]bool{
+	"-d": true, "--detach": true, "--rm": true, "-i": true, "--interactive": true,
+	"-t": true, "--tty": true, "-it": true, "--privileged": true, "--init": true,
+	"-q": true, "--quiet": true,
+}
+
+// qualifyImageArgs rewrites the image of a run, create or pull command to its
+// fully qualified docker.io name (e.g. "mongo" to "docker.io/library/mongo")
+func qualifyImageArgs(args []string) []string {
+	if len(args) == 0 || (args[0] != "run" && args[0] != "create" && args[0] != "pull") {
+		return args
+	}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if !strings.Contains(arg, "=") && !booleanRunFlags[arg] {
+				i++ // skip the flag's value
+			}
+			continue
+		}
+		qualified := make([]string, len(args))
+		copy(qualified, args)
+		qualified[i] = QualifyImage(arg)
+		return qualified
+	}
+	return args
+}
+
+// QualifyImage returns the fully qualified name of a Docker Hub image;
+// images that already name a registry are returned unchanged
+func QualifyImage(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return image
+	}
+	if len(parts) == 1 {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + image
+}
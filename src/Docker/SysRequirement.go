@@ -9,41 +9,58 @@ import (
 	"strings"
 )
 
-func CheckSystemRequirements() {
-	// Check if Docker is installed and accessible
+// CheckResult is the outcome of one preflight or doctor check
+type CheckResult struct {
+	Name        string   `json:"name"`
+	Status      string   `json:"status"` // CheckPass, CheckWarn or CheckFail
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation,omitempty"`
+	Details     []string `json:"details,omitempty"` // e.g. the last log lines of a failing container
+}
+
+// Statuses of a CheckResult
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// CheckSystemRequirements runs the checks every ContainDB setup depends on:
+// Docker available, at least 2GB of RAM and 10GB of free disk for Docker
+func CheckSystemRequirements() []CheckResult {
+	results := []CheckResult{}
+
 	if err := checkDockerInstallation(); err != nil {
-		fmt.Printf("WARNING: %v\n", err)
+		results = append(results, CheckResult{Name: "Docker", Status: CheckFail, Message: err.Error(),
+			Remediation: "Install Docker with `containDB --install-docker` or start Docker Desktop"})
+	} else {
+		results = append(results, CheckResult{Name: "Docker", Status: CheckPass, Message: "Docker CLI is available"})
 	}
 
-	// Check if system has enough RAM (minimum 2GB recommended)
-	if err := checkRAM(2); err != nil {
-		fmt.Printf("WARNING: %v\n", err)
-		fmt.Println("Exiting program due to RAM requirement failure")
-		os.Exit(1)
-	}
+	results = append(results, CheckRAM(2), CheckDiskSpace(10))
+	return results
+}
 
-	// Check if system has enough disk space (minimum 10GB recommended)
-	if err := checkDiskSpace(10); err != nil {
-		fmt.Printf("WARNING: %v\n", err)
-		fmt.Println("Exiting program due to disk space requirement failure")
-		os.Exit(1)
+// HasFailure reports whether any of the results failed
+func HasFailure(results []CheckResult) bool {
+	for _, result := range results {
+		if result.Status == CheckFail {
+			return true
+		}
 	}
-
-	fmt.Println("All system requirements checks passed!")
+	return false
 }
 
 func checkDockerInstallation() error {
-	cmd := exec.Command("docker", "--version")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
+	cmd := Command("--version")
+	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("docker is not installed or not accessible: %v", err)
 	}
-
-	fmt.Printf("Docker is available: %s\n", strings.TrimSpace(string(output)))
 	return nil
 }
 
-func checkRAM(minGB float64) error {
+// hostRAMGB returns the total RAM of the host in GB, or 0 when it cannot be detected
+func hostRAMGB() float64 {
 	var totalGB float64
 
 	switch runtime.GOOS {
@@ -51,7 +68,7 @@ func checkRAM(minGB float64) error {
 		// Read /proc/meminfo
 		data, err := os.ReadFile("/proc/meminfo")
 		if err != nil {
-			return fmt.Errorf("failed to read /proc/meminfo: %v", err)
+			return 0
 		}
 		for _, line := range strings.Split(string(data), "\n") {
 			if strings.HasPrefix(line, "MemTotal:") {
@@ -98,35 +115,76 @@ func checkRAM(minGB float64) error {
 		runtime.ReadMemStats(&m)
 		totalGB = float64(m.Sys) / (1024 * 1024 * 1024)
 	}
+	return totalGB
+}
 
+// CheckRAM verifies that the machine running the containers has at least minGB of RAM
+func CheckRAM(minGB float64) CheckResult {
+	result := CheckResult{Name: "Memory"}
+	totalGB := hostRAMGB()
+	if IsRemoteEngine() {
+		totalGB = 0
+		if out, err := runtimeInfo("memtotal"); err == nil {
+			if bytes, err := strconv.ParseFloat(out, 64); err == nil {
+				totalGB = bytes / (1024 * 1024 * 1024)
+			}
+		}
+	}
+	switch {
+	case totalGB == 0:
+		result.Status, result.Message = CheckWarn, "could not detect system RAM"
+	case totalGB < minGB:
+		result.Status = CheckFail
+		result.Message = fmt.Sprintf("insufficient RAM. Detected: %.2f GB, Required: %.2f GB", totalGB, minGB)
+		result.Remediation = "Close other applications or stop unused containers with `containDB stop <container>`"
+		if IsMacOS() || IsWindows() {
+			result.Remediation = "Raise the memory of Docker Desktop in Settings > Resources"
+		}
+	default:
+		result.Status, result.Message = CheckPass, fmt.Sprintf("%.2f GB total, %.2f GB required", totalGB, minGB)
+	}
+	return result
+}
+
+// CheckMemoryLimit verifies that a container memory limit fits in the memory
+// available to Docker: the host RAM, or the VM of Docker Desktop when smaller
+func CheckMemoryLimit(limitMB int64) error {
+	totalGB := 0.0
+	if !IsRemoteEngine() {
+		totalGB = hostRAMGB()
+	}
+	if out, err := runtimeInfo("memtotal"); err == nil {
+		if bytes, err := strconv.ParseFloat(out, 64); err == nil && bytes > 0 {
+			if dockerGB := bytes / (1024 * 1024 * 1024); totalGB == 0 || dockerGB < totalGB {
+				totalGB = dockerGB
+			}
+		}
+	}
 	if totalGB == 0 {
-		fmt.Println("Warning: Could not detect system RAM, skipping check.")
+		fmt.Println("Warning: Could not detect system RAM, skipping memory limit check.")
 		return nil
 	}
 
-	if totalGB < minGB {
-		return fmt.Errorf("insufficient RAM. Detected: %.2f GB, Required: %.2f GB", totalGB, minGB)
+	limitGB := float64(limitMB) / 1024
+	if limitGB > totalGB {
+		return fmt.Errorf("memory limit %.2f GB exceeds the %.2f GB available to Docker", limitGB, totalGB)
+	}
+	if limitGB > totalGB*0.75 {
+		fmt.Printf("⚠️  Memory limit %.2f GB uses most of the %.2f GB available to Docker, other containers may be starved.\n", limitGB, totalGB)
 	}
-
-	fmt.Printf("RAM check passed. Total: %.2f GB\n", totalGB)
 	return nil
 }
 
-func checkDiskSpace(minGB float64) error {
-	var path string
-	if runtime.GOOS == "windows" {
-		path = "C:\\"
-	} else {
-		path = "/"
-	}
-
+// freeDiskGB returns the free space in GB of the file system holding path,
+// or 0 when it cannot be detected
+func freeDiskGB(path string) float64 {
 	freeGB := 0.0
 
 	if runtime.GOOS == "windows" {
 		cmd := exec.Command("powershell", "-Command", "Get-PSDrive C | Select-Object Free")
 		output, err := cmd.Output()
 		if err != nil {
-			return fmt.Errorf("failed to check disk space: %v", err)
+			return 0
 		}
 
 		lines := strings.Split(string(output), "\n")
@@ -144,42 +202,68 @@ func checkDiskSpace(minGB float64) error {
 		cmd := exec.Command("df", "-kP", path)
 		output, err := cmd.Output()
 		if err != nil {
-			return fmt.Errorf("failed to check disk space: %v", err)
+			return 0
 		}
+		freeGB = parseDfAvailableGB(string(output))
+	}
+	return freeGB
+}
 
-		// Parse Unix/Linux output
-		// Filesystem 1024-blocks Used Available Capacity Mounted on
-		lines := strings.Split(string(output), "\n")
-		for i, line := range lines {
-			if i == 0 {
-				continue // Skip header
+// parseDfAvailableGB returns the available space of `df -kP` output in GB
+func parseDfAvailableGB(output string) float64 {
+	// Filesystem 1024-blocks Used Available Capacity Mounted on
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			continue // Skip header
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 4 {
+			// Available is the 4th field in -kP output on both Linux and macOS
+			if val, err := strconv.ParseFloat(fields[3], 64); err == nil {
+				return val / (1024 * 1024) // KB to GB
 			}
-			fields := strings.Fields(line)
-			if len(fields) >= 4 {
-				// Available is usually the 4th field in -kP output
-				// Linux: Filesystem 1k-blocks Used Available Use% Mounted on
-				// macOS: Filesystem 1024-blocks Used Available Capacity Mounted on
-				// Sometimes strict POSIX might vary but usually Available is 4th.
-
-				// Check which field matches numbers
-				idx := 3 // 0-indexed, so 4th field
-				if val, err := strconv.ParseFloat(fields[idx], 64); err == nil {
-					freeGB = val / (1024 * 1024) // KB to GB
-					break
+		}
+	}
+	return 0
+}
+
+// CheckDiskSpace verifies that the disk Docker stores images and volumes on
+// has at least minGB free
+func CheckDiskSpace(minGB float64) CheckResult {
+	path := "/"
+	if runtime.GOOS == "windows" {
+		path = "C:\\"
+	} else if runtime.GOOS == "linux" {
+		// Images and volumes live in Docker's data root, often a separate disk
+		if root, err := runtimeInfo("rootdir"); err == nil {
+			if root != "" {
+				if _, err := os.Stat(root); err == nil {
+					path = root
 				}
 			}
 		}
 	}
 
-	if freeGB == 0 {
-		fmt.Println("Warning: Could not detect free disk space, skipping check.")
-		return nil
+	result := CheckResult{Name: "Disk space"}
+	freeGB := 0.0
+	if IsRemoteEngine() {
+		// The container root lives on the remote engine's data disk
+		if out, err := runOnEngine("df -kP /"); err == nil {
+			freeGB = parseDfAvailableGB(out)
+		}
+	} else {
+		freeGB = freeDiskGB(path)
 	}
-
-	if freeGB < minGB {
-		return fmt.Errorf("insufficient disk space. Available: %.2f GB, Required: %.2f GB", freeGB, minGB)
+	switch {
+	case freeGB == 0:
+		result.Status, result.Message = CheckWarn, "could not detect free disk space"
+	case freeGB < minGB:
+		result.Status = CheckFail
+		result.Message = fmt.Sprintf("insufficient disk space. Available: %.2f GB, Required: %.2f GB", freeGB, minGB)
+		result.Remediation = "Free space with `docker system prune` or remove unused images and volumes from the ContainDB menu"
+	default:
+		result.Status, result.Message = CheckPass, fmt.Sprintf("%.2f GB free, %.2f GB required", freeGB, minGB)
 	}
-
-	fmt.Printf("Disk space check passed. Available: %.2f GB\n", freeGB)
-	return nil
+	return result
 }
@@ -3,13 +3,12 @@ package Docker
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 )
 
 // ListRunningDatabases returns names of containers on the ContainDB-Network
 func ListRunningDatabases() ([]string, error) {
-	cmd := exec.Command("docker", "ps",
+	cmd := Command("ps",
 		"--filter", "network=ContainDB-Network",
 		"--format", "{{.Names}}")
 	out, err := cmd.Output()
@@ -26,15 +25,21 @@ func ListRunningDatabases() ([]string, error) {
 // RemoveDatabase forcibly removes the given container,
 // optionally deleting its associated data volumes.
 func RemoveDatabase(name string) error {
-	// ask user whether to delete attached volumes
-	deleteVolumes := AskYesNo("Do you want to delete associated data volumes?")
-
 	// Get container type from name (assuming container name follows pattern like "mongodb-container")
 	containerType := ""
 	if parts := strings.Split(name, "-"); len(parts) > 0 {
 		containerType = parts[0] // Extract database type from container name
 	}
 
+	// ask user whether to delete attached volumes; embedded databases (sqlite, duckdb)
+	// live in a host file that is never deleted here
+	deleteVolumes := false
+	if containerType == "sqlite" || containerType == "duckdb" {
+		fmt.Println("ℹ️  The database file on the host is kept; only the web front-end is removed.")
+	} else {
+		deleteVolumes = AskYesNo("Do you want to delete associated data volumes?")
+	}
+
 	// First remove the container itself
 	args := []string{"rm", "-f"}
 	if deleteVolumes {
@@ -42,7 +47,7 @@ func RemoveDatabase(name string) error {
 	}
 	args = append(args, name)
 
-	cmd := exec.Command("docker", args...)
+	cmd := Command(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	err := cmd.Run()
@@ -70,8 +75,14 @@ func ListDatabaseImages() ([]string, error) {
 	dbImages := []string{
 		// Core databases
 		"mongo", "mysql", "postgres", "redis", "mariadb",
+		// Document / key-value stores
+		"couchdb", "couchbase", "amazon/dynamodb-local", "valkey/valkey",
+		"eqalpha/keydb", "quay.io/coreos/etcd",
+		// Embedded database front-ends
+		"coleifer/sqlite-web", "dbeaver/cloudbeaver",
 		// Management tools (core)
-		"phpmyadmin", "dpage/pgadmin4", "redis/redisinsight",
+		"phpmyadmin", "dpage/pgadmin4", "redis/redisinsight", "adminer",
+		"mongo-express",
 		// Vector databases
 		"qdrant/qdrant", "cr.weaviate.io/semitechnologies/weaviate",
 		"milvusdb/milvus", "chromadb/chroma", "pgvector/pgvector",
@@ -82,7 +93,7 @@ func ListDatabaseImages() ([]string, error) {
 	}
 
 	// Build docker command to list images
-	cmd := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+	cmd := Command("images", "--format", "{{.Repository}}:{{.Tag}}")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list images: %v", err)
@@ -110,7 +121,7 @@ func ListDatabaseImages() ([]string, error) {
 
 // IsImageInUse checks if the given image is currently used by any running container
 func IsImageInUse(image string) (bool, string, error) {
-	cmd := exec.Command("docker", "ps", "--format", "{{.Image}} {{.Names}}", "--filter", fmt.Sprintf("ancestor=%s", image))
+	cmd := Command("ps", "--format", "{{.Image}} {{.Names}}", "--filter", fmt.Sprintf("ancestor=%s", image))
 	output, err := cmd.Output()
 	if err != nil {
 		return false, "", fmt.Errorf("failed to check if image is in use: %v", err)
@@ -130,7 +141,7 @@ func IsImageInUse(image string) (bool, string, error) {
 
 // RemoveImage removes a Docker image
 func RemoveImage(image string) error {
-	cmd := exec.Command("docker", "rmi", image)
+	cmd := Command("rmi", image)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
@@ -145,18 +156,29 @@ func ListContainDBVolumes() ([]string, error) {
 	prefixes := []string{
 		// Core databases
 		"mongodb-data", "mysql-data", "postgresql-data", "redis-data", "mariadb-data",
+		// Document / key-value stores
+		"couchdb-data", "couchbase-data", "dynamodb-data", "valkey-data",
+		"keydb-data", "etcd-data",
 		// Vector databases
 		"qdrant-data", "weaviate-data", "milvus-data", "chroma-data", "pgvector-data",
 		"redis-stack-data", "elasticsearch-data", "opensearch-data",
 		"vespa-data", "typesense-data",
 	}
 
-	cmd := exec.Command("docker", "volume", "ls", "--format", "{{.Name}}")
+	cmd := Command("volume", "ls", "--format", "{{.Name}}")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list volumes: %v", err)
 	}
 
+	// Volumes of imported compose services carry the managed label instead
+	managed := make(map[string]bool)
+	if out, err := Command("volume", "ls", "--filter", "label="+ManagedLabel+"=true", "--format", "{{.Name}}").Output(); err == nil {
+		for _, name := range strings.Fields(string(out)) {
+			managed[name] = true
+		}
+	}
+
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	var volumes []string
 	for _, line := range lines {
@@ -164,6 +186,10 @@ func ListContainDBVolumes() ([]string, error) {
 		if line == "" {
 			continue
 		}
+		if managed[line] {
+			volumes = append(volumes, line)
+			continue
+		}
 
 		// Check if this volume matches any of our database patterns
 		for _, prefix := range prefixes {
@@ -179,7 +205,7 @@ func ListContainDBVolumes() ([]string, error) {
 
 // IsVolumeInUse checks if the given volume is currently used by any container
 func IsVolumeInUse(volume string) (bool, string, error) {
-	cmd := exec.Command("docker", "ps", "-a", "--filter", fmt.Sprintf("volume=%s", volume), "--format", "{{.Names}}")
+	cmd := Command("ps", "-a", "--filter", fmt.Sprintf("volume=%s", volume), "--format", "{{.Names}}")
 	output, err := cmd.Output()
 	if err != nil {
 		return false, "", fmt.Errorf("failed to check if volume is in use: %v", err)
@@ -31,9 +31,9 @@ func AskYesNo(label string) bool {
 func IsContainerRunning(nameOrImage string, checkByName bool) bool {
 	var cmd *exec.Cmd
 	if checkByName {
-		cmd = exec.Command("docker", "ps", "--filter", fmt.Sprintf("name=%s", nameOrImage), "--format", "{{.Names}}")
+		cmd = Command("ps", "--filter", fmt.Sprintf("name=%s", nameOrImage), "--format", "{{.Names}}")
 	} else {
-		cmd = exec.Command("docker", "ps", "--filter", fmt.Sprintf("ancestor=%s", nameOrImage), "--format", "{{.Names}}")
+		cmd = Command("ps", "--filter", fmt.Sprintf("ancestor=%s", nameOrImage), "--format", "{{.Names}}")
 	}
 	output, _ := cmd.Output()
 	return strings.TrimSpace(string(output)) != ""
@@ -45,7 +45,7 @@ func ListOfContainers(images []string) []string {
 	}
 
 	// Get all running containers with their names and images
-	cmd := exec.Command("docker", "ps", "--format", "{{.Names}} {{.Image}}")
+	cmd := Command("ps", "--format", "{{.Names}} {{.Image}}")
 	output, err := cmd.Output()
 	if err != nil {
 		return []string{}
@@ -79,16 +79,113 @@ func ListOfContainers(images []string) []string {
 	return containers
 }
 
+// GetContainerImage returns the image a container was created from
+func GetContainerImage(name string) string {
+	cmd := Command("inspect", "--format", "{{.Config.Image}}", name)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// engineImages maps image name fragments to ContainDB engine names. The list is
+// ordered so that more specific fragments (pgvector, redis-stack, tool images
+// built on an engine's name) match before generic ones.
+var engineImages = []struct {
+	fragment string
+	engine   string
+}{
+	// Management tools whose image names contain an engine name
+	{"redisinsight", ""},
+	{"opensearch-dashboards", ""},
+	{"mongo-express", ""},
+	// Databases
+	{"pgvector", "pgvector"},
+	{"redis-stack", "redis-stack"},
+	{"opensearch", "opensearch"},
+	{"elasticsearch", "elasticsearch"},
+	{"mariadb", "mariadb"},
+	{"mysql", "mysql"},
+	{"postgres", "postgresql"},
+	{"mongo", "mongodb"},
+	{"valkey", "valkey"},
+	{"keydb", "keydb"},
+	{"redis", "redis"},
+	{"couchdb", "couchdb"},
+	{"couchbase", "couchbase"},
+	{"dynamodb-local", "dynamodb"},
+	{"etcd", "etcd"},
+	{"axiodb", "axiodb"},
+	{"qdrant", "qdrant"},
+	{"weaviate", "weaviate"},
+	{"milvus", "milvus"},
+	{"chroma", "chroma"},
+	{"marqo", "marqo"},
+	{"vespa", "vespa"},
+	{"typesense", "typesense"},
+	{"sqlite-web", "sqlite"},
+	{"cloudbeaver", "duckdb"},
+}
+
+// DetectEngine returns the ContainDB engine name (e.g. "postgresql", "mongodb")
+// of a container based on its image, or an empty string for unknown images and
+// management tools
+func DetectEngine(name string) string {
+	return engineOfImage(GetContainerImage(name))
+}
+
+// engineOfImage returns the ContainDB engine name of an image reference
+func engineOfImage(image string) string {
+	// Ignore the tag, e.g. "pgvector/pgvector:pg17"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	for _, e := range engineImages {
+		if strings.Contains(image, e.fragment) {
+			return e.engine
+		}
+	}
+	return ""
+}
+
+// GetContainerEnv returns the value of an environment variable set on a container,
+// or an empty string when it is not set
+func GetContainerEnv(name, key string) string {
+	cmd := Command("inspect", "--format", "{{range .Config.Env}}{{println .}}{{end}}", name)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, key+"=") {
+			return strings.TrimPrefix(line, key+"=")
+		}
+	}
+	return ""
+}
+
+// GetContainerMountSource returns the host path or volume mounted at dest inside a container
+func GetContainerMountSource(name, dest string) string {
+	format := fmt.Sprintf("{{range .Mounts}}{{if eq .Destination %q}}{{.Source}}{{end}}{{end}}", dest)
+	cmd := Command("inspect", "--format", format, name)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // VolumeExists returns true if Docker volume with given name exists
 func VolumeExists(name string) bool {
-	cmd := exec.Command("docker", "volume", "inspect", name)
+	cmd := Command("volume", "inspect", name)
 	err := cmd.Run()
 	return err == nil
 }
 
 // CreateVolume creates a Docker volume with given name
 func CreateVolume(name string) error {
-	cmd := exec.Command("docker", "volume", "create", name)
+	cmd := Command("volume", "create", name)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -102,7 +199,7 @@ func RemoveVolume(name string) error {
 	}
 
 	fmt.Printf("Removing volume %s...\n", name)
-	cmd := exec.Command("docker", "volume", "rm", "-f", name)
+	cmd := Command("volume", "rm", "-f", name)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
@@ -8,7 +8,7 @@ import (
 )
 
 func IsDockerInstalled() bool {
-	cmd := exec.Command("docker", "--version")
+	cmd := Command("--version")
 	err := cmd.Run()
 	return err == nil
 }
@@ -40,7 +40,7 @@ func installDockerLinux() error {
 		$(. /etc/os-release && echo ${UBUNTU_CODENAME:-$VERSION_CODENAME}) stable" | sudo tee /etc/apt/sources.list.d/docker.list > /dev/null`,
 		"sudo apt-get update",
 		"sudo apt-get install -y docker-ce docker-ce-cli containerd.io docker-buildx-plugin docker-compose-plugin",
-		"sudo usermod -aG docker $USER",
+		"sudo usermod -aG docker ${SUDO_USER:-$USER}",
 		"sudo apt install -y docker-compose-plugin",
 	}
 
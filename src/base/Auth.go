@@ -0,0 +1,182 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// authSecrets names the secret of engines that are started with
+// authentication unless the install opts out
+var authSecrets = map[string]string{
+	"mongodb":     "root password",
+	"redis":       "password",
+	"redis-stack": "password",
+	"valkey":      "password",
+	"keydb":       "password",
+	"qdrant":      "API key",
+	"chroma":      "auth token",
+	"weaviate":    "API key",
+	"milvus":      "root password",
+}
+
+// milvusDefaultPassword is the password of Milvus' root user until it is changed
+const milvusDefaultPassword = "Milvus"
+
+// resolveAuthSecret returns the secret protecting a new instance, generated or
+// entered, or "" when authentication is turned off
+func resolveAuthSecret(database string, noAuth bool) (string, error) {
+	label, ok := authSecrets[database]
+	if !ok {
+		return "", nil
+	}
+	if noAuth {
+		fmt.Printf("⚠️  Authentication disabled for %s — throwaway development only, anyone who can reach the port has full access.\n", database)
+		return "", nil
+	}
+
+	items := []string{
+		fmt.Sprintf("Generate a random %s (recommended)", label),
+		fmt.Sprintf("Enter a %s", label),
+		"No authentication (throwaway development only)",
+	}
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Authentication for %s", database),
+		Items: items,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	switch index {
+	case 0:
+		secret, err := generateSecret(24)
+		if err != nil {
+			return "", err
+		}
+		fmt.Printf("🔑 Generated %s: %s\n", label, secret)
+		fmt.Println("   Store it safely; ContainDB keeps it only in the container's environment.")
+		return secret, nil
+	case 1:
+		secret := tools.AskForInput(fmt.Sprintf("Enter %s", label), "")
+		if secret == "" {
+			return "", fmt.Errorf("%s cannot be empty", label)
+		}
+		if database == "milvus" && (len(secret) < 6 || secret == milvusDefaultPassword) {
+			return "", fmt.Errorf("the Milvus root password must be at least 6 characters and differ from the default")
+		}
+		return secret, nil
+	}
+	return resolveAuthSecret(database, true)
+}
+
+// generateSecret returns a random alphanumeric secret, safe in URLs and shell words
+func generateSecret(length int) (string, error) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	secret := make([]byte, length)
+	for i := range secret {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		secret[i] = alphabet[n.Int64()]
+	}
+	return string(secret), nil
+}
+
+// authEnv returns the environment enabling authentication with secret
+func authEnv(database, secret string) []string {
+	switch database {
+	case "redis", "redis-stack", "valkey", "keydb":
+		// Not read by the server (see redisAuthArgs); ContainDB and the tools take it from here
+		return []string{"REDIS_PASSWORD=" + secret}
+	case "qdrant":
+		return []string{"QDRANT__SERVICE__API_KEY=" + secret}
+	case "chroma":
+		// Read by the 0.x server only; the image is pinned to it
+		return []string{
+			"CHROMA_SERVER_AUTHN_PROVIDER=chromadb.auth.token_authn.TokenAuthenticationServerProvider",
+			"CHROMA_SERVER_AUTHN_CREDENTIALS=" + secret,
+		}
+	case "weaviate":
+		return []string{
+			"AUTHENTICATION_APIKEY_ENABLED=true",
+			"AUTHENTICATION_APIKEY_ALLOWED_KEYS=" + secret,
+			"AUTHENTICATION_APIKEY_USERS=admin",
+		}
+	case "milvus":
+		// Milvus keeps its users itself; the password is set after the start (see setupMilvus)
+		return []string{"MILVUS_ROOT_PASSWORD=" + secret}
+	}
+	return nil
+}
+
+// redisAuthArgs returns the server options requiring password for the default
+// user. Only its hash is passed, so the password does not show in the command;
+// it stays readable in the REDIS_PASSWORD env with `docker inspect`.
+func redisAuthArgs(password string) []string {
+	hash := sha256.Sum256([]byte(password))
+	return []string{"--user", "default", "reset", "on", "#" + hex.EncodeToString(hash[:]), "~*", "&*", "+@all"}
+}
+
+// redisServerCommand passes options to the server of a Redis-compatible image:
+// as the command, or through REDIS_ARGS for Redis Stack
+func redisServerCommand(database string, options []string) (env []string, command []string) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+	switch database {
+	case "redis-stack":
+		return []string{"REDIS_ARGS=" + strings.Join(options, " ")}, nil
+	case "valkey":
+		return nil, append([]string{"valkey-server"}, options...)
+	case "keydb":
+		return nil, append([]string{"keydb-server", "/etc/keydb/keydb.conf"}, options...)
+	}
+	return nil, append([]string{"redis-server"}, options...)
+}
+
+// milvusAuthConfig turns on authentication; Milvus reads it from user.yaml on top of its defaults
+const milvusAuthConfig = `common:
+  security:
+    authorizationEnabled: true
+`
+
+// copyContentToContainer writes content to path in a created container
+func copyContentToContainer(containerName, path, content string) error {
+	file, err := os.CreateTemp("", "containdb-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	if out, err := Docker.Command("cp", file.Name(), containerName+":"+path).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy %s: %v %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// redisAuthEnv returns the environment the CLI of a Redis-compatible container
+// needs to authenticate, empty when it has no password
+func redisAuthEnv(name string) []string {
+	password := Docker.GetContainerEnv(name, "REDIS_PASSWORD")
+	if password == "" {
+		return nil
+	}
+	// valkey-cli reads its own variable, redis-cli and keydb-cli the Redis one
+	return []string{"REDISCLI_AUTH=" + password, "VALKEYCLI_AUTH=" + password}
+}
@@ -7,15 +7,29 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 
 	"github.com/manifoldco/promptui"
 )
 
+// toolContainers are management tool containers, hidden from "List Databases"
+var toolContainers = map[string]bool{
+	"phpmyadmin": true, "pgadmin": true, "redisinsight": true, "adminer": true,
+	"attu-container": true, "mongo-express": true, "kibana-container": true,
+	"opensearch-dashboards-container": true, tools.GatewayContainer: true,
+}
+
+// isToolContainer reports whether a container runs a management tool (or the SSH
+// tunnel of one, named "<tool>-tunnel") rather than a database
+func isToolContainer(name string) bool {
+	return toolContainers[name] || strings.HasSuffix(name, "-tunnel")
+}
+
 func BaseCaseHandler() {
 	// Top-level action menu
 	actionPrompt := promptui.Select{
 		Label: "What do you want to do?",
-		Items: []string{"Install Database", "List Databases", "Remove Database", "Remove Image", "Remove Volume", "Import Services", "Export Services", "Update ContainDB", "Exit"},
+		Items: []string{"Install Database", "List Databases", "View Logs", "Profile Queries", "Seed Database", "Manage Database", "Remove Database", "Remove Image", "Remove Volume", "Import Services", "Export Services", "HTTPS Gateway", "Update ContainDB", "Exit"},
 	}
 	_, action, err := actionPrompt.Run()
 	if err != nil {
@@ -27,62 +41,58 @@ func BaseCaseHandler() {
 	switch action {
 	case "Install Database":
 		database := SelectDatabase()
-		switch database {
-		case "phpmyadmin":
-			tools.StartPHPMyAdmin()
-		case "MongoDB Compass":
-			tools.DownloadMongoDBCompass()
-		case "PgAdmin":
-			tools.StartPgAdmin()
-		case "Redis Insight":
-			tools.StartRedisInsight()
-		case "Attu":
-			tools.StartAttu()
-		case "Kibana":
-			tools.StartKibana()
-		case "OpenSearch Dashboards":
-			tools.StartOpenSearchDashboards()
-		default:
-			StartContainer(database)
-		}
+		InstallService(database, InstallOptions{})
 
 	case "List Databases":
-		names, err := Docker.ListRunningDatabases()
-
-		// Filter out management tool containers — show only database containers
-		toolContainers := map[string]bool{
-			"phpmyadmin": true, "pgadmin": true, "redisinsight": true,
-			"attu-container": true, "kibana-container": true,
-			"opensearch-dashboards-container": true,
-		}
-		var filtered []string
-		for _, name := range names {
-			if !toolContainers[name] {
-				filtered = append(filtered, name)
-			}
-		}
-		names = filtered
+		instances, err := Docker.ListContainDBInstances()
 		if err != nil {
 			fmt.Println("Error listing databases:", err)
 			return
 		}
-		if len(names) == 0 {
-			fmt.Println("No running databases found.")
+
+		// Filter out management tool containers — show only database containers
+		var databases []Docker.ContainerState
+		for _, instance := range instances {
+			if !isToolContainer(instance.Name) {
+				databases = append(databases, instance)
+			}
+		}
+		if len(databases) == 0 {
+			fmt.Println("No databases found.")
 		} else {
-			fmt.Println("Running databases:")
-			for _, n := range names {
-				fmt.Println(" -", n)
+			fmt.Println("Databases:")
+			for _, db := range databases {
+				fmt.Printf(" - %s [%s] %s\n", db.Name, db.State, db.Status)
 			}
 		}
 
+	case "View Logs":
+		ViewLogsInteractive()
+
+	case "Profile Queries":
+		ProfileInteractive()
+
+	case "Seed Database":
+		SeedInteractive()
+
+	case "HTTPS Gateway":
+		GatewayInteractive()
+
+	case "Manage Database":
+		ManageDatabase()
+
 	case "Remove Database":
-		names, err := Docker.ListRunningDatabases()
+		instances, err := Docker.ListContainDBInstances()
 		if err != nil {
 			fmt.Println("Error listing databases:", err)
 			return
 		}
+		var names []string
+		for _, instance := range instances {
+			names = append(names, instance.Name)
+		}
 		if len(names) == 0 {
-			fmt.Println("No running databases to remove.")
+			fmt.Println("No databases to remove.")
 		} else {
 			items := append(names, "Exit")
 			sel := promptui.Select{
@@ -197,19 +207,7 @@ func BaseCaseHandler() {
 			fmt.Printf("✅ Volume '%s' removed successfully\n", selected)
 		}
 	case "Export Services":
-		fmt.Println("Exporting Docker Compose file with all running services...")
-		fmt.Println("\n⚠️  IMPORTANT: The export functionality only exports container configurations, not the actual data.")
-		fmt.Println("   Even if you used data persistence during installation, the exported compose file only")
-		fmt.Println("   references local volume paths from your current machine which won't exist on other systems.")
-		fmt.Print("   For data backup, please use each database's native backup tools.\n\n")
-
-		filePath := Docker.MakeDockerComposeWithAllServices()
-		if filePath == "" {
-			fmt.Println("Failed to create Docker Compose file.")
-		} else {
-			fmt.Println("\n✅ Docker Compose file created successfully at:", filePath)
-			fmt.Println("   This file contains only the configuration of your containers.")
-		}
+		ExportInteractive()
 	case "Import Services":
 		fmt.Println("Importing services from Docker Compose file...")
 		fmt.Println("\n⚠️  IMPORTANT: The import functionality requires a valid docker-compose.yml file.")
@@ -273,3 +271,30 @@ func BaseCaseHandler() {
 		return
 	}
 }
+
+// InstallService starts the selected database or management tool. It is shared by
+// the interactive menu and the `containdb install` subcommand.
+func InstallService(service string, opts InstallOptions) {
+	switch service {
+	case "phpmyadmin":
+		tools.StartPHPMyAdmin()
+	case "MongoDB Compass":
+		tools.DownloadMongoDBCompass()
+	case "Mongo Express":
+		tools.StartMongoExpress()
+	case "PgAdmin":
+		tools.StartPgAdmin()
+	case "Adminer":
+		tools.StartAdminer()
+	case "Redis Insight":
+		tools.StartRedisInsight()
+	case "Attu":
+		tools.StartAttu()
+	case "Kibana":
+		tools.StartKibana()
+	case "OpenSearch Dashboards":
+		tools.StartOpenSearchDashboards()
+	default:
+		StartContainer(service, opts)
+	}
+}
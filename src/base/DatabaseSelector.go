@@ -11,12 +11,14 @@ import (
 var categoryItems = map[string][]string{
 	"SQL Database": {
 		"mysql", "postgresql", "mariadb", "pgvector",
-		"phpmyadmin", "PgAdmin",
+		"sqlite", "duckdb",
+		"phpmyadmin", "PgAdmin", "Adminer",
 		"Back",
 	},
 	"NoSQL Database": {
 		"mongodb", "axiodb", "redis",
-		"MongoDB Compass", "Redis Insight",
+		"couchdb", "couchbase", "dynamodb", "valkey", "keydb", "etcd",
+		"MongoDB Compass", "Mongo Express", "Redis Insight",
 		"Back",
 	},
 	"Vector Database": {
@@ -62,3 +64,15 @@ func SelectDatabase() string {
 		return result
 	}
 }
+
+// IsKnownService reports whether name is a database or tool offered in the install menu.
+func IsKnownService(name string) bool {
+	for _, items := range categoryItems {
+		for _, item := range items {
+			if item != "Back" && item == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// doctorReport is the --json form of the doctor output, meant to be attached
+// to bug reports
+type doctorReport struct {
+	OS      string               `json:"os"`
+	Arch    string               `json:"arch"`
+	Healthy bool                 `json:"healthy"`
+	Checks  []Docker.CheckResult `json:"checks"`
+}
+
+// DoctorCommand handles `containdb doctor [--json]`. It diagnoses the host, the
+// Docker installation and the ContainDB containers, and exits non-zero when a
+// check failed.
+func DoctorCommand(args []string) {
+	doctorFlags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	jsonOutput := doctorFlags.Bool("json", false, "print the report as JSON")
+	doctorFlags.Parse(args)
+
+	results := runDoctorChecks()
+	healthy := !Docker.HasFailure(results)
+
+	if *jsonOutput {
+		report := doctorReport{OS: Docker.GetOSName(), Arch: Docker.DockerArch(), Healthy: healthy, Checks: results}
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		fmt.Println("🩺 ContainDB doctor")
+		printCheckResults(results)
+		if healthy {
+			fmt.Println("\n✅ All checks passed!")
+		} else {
+			fmt.Println("\n❌ Some checks failed, see the hints above.")
+		}
+	}
+
+	if !healthy {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func runDoctorChecks() []Docker.CheckResult {
+	results := Docker.CheckSystemRequirements()
+
+	daemon, reachable := Docker.CheckDockerDaemon()
+	results = append(results, daemon, Docker.CheckDockerSocket())
+	if !reachable {
+		// Everything below talks to the daemon
+		return results
+	}
+	// Only Elasticsearch and OpenSearch use the setting
+	if hasSearchInstance() {
+		results = append(results, Docker.CheckMaxMapCount())
+	}
+
+	results = append(results,
+		Docker.CheckResult{Name: "Architecture", Status: Docker.CheckPass, Message: "Docker host runs " + Docker.DockerArch()},
+		Docker.CheckComposePlugin(),
+		Docker.CheckContainDBNetwork(),
+		Docker.CheckOrphanedVolumes(),
+	)
+	results = append(results, Docker.CheckRestartLoops()...)
+	results = append(results, Docker.CheckPortConflicts()...)
+	results = append(results, Docker.CheckDockerDiskUsage())
+	return results
+}
+
+// hasSearchInstance reports whether an Elasticsearch or OpenSearch instance is installed
+func hasSearchInstance() bool {
+	instances, err := Docker.ListContainDBInstances()
+	if err != nil {
+		return false
+	}
+	for _, instance := range instances {
+		if engine := Docker.DetectEngine(instance.Name); engine == "elasticsearch" || engine == "opensearch" {
+			return true
+		}
+	}
+	return false
+}
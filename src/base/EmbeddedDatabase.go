@@ -0,0 +1,62 @@
+package base
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// embeddedEngines are file-based databases. ContainDB mounts the directory that
+// holds the database file into a container running a web front-end for it.
+var embeddedEngines = map[string]string{
+	"sqlite": ".db",
+	"duckdb": ".duckdb",
+}
+
+func isEmbeddedEngine(database string) bool {
+	_, ok := embeddedEngines[database]
+	return ok
+}
+
+// resolveEmbeddedFile returns the absolute path of the database file to serve,
+// prompting for it when none was passed with --file.
+func resolveEmbeddedFile(database, file string) (string, error) {
+	ext := embeddedEngines[database]
+	if file == "" {
+		selected, err := SelectFilePath(fmt.Sprintf("Enter path to the %s database file", database), "app"+ext, ext)
+		if err != nil {
+			return "", err
+		}
+		file = selected
+	}
+
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return "", fmt.Errorf("invalid database file path %s: %v", file, err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err == nil && info.IsDir() {
+		return "", fmt.Errorf("%s is a directory, expected a database file", absPath)
+	}
+	if os.IsNotExist(err) {
+		// The engine creates the file on first use; only its directory has to exist
+		if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory for %s: %v", absPath, err)
+		}
+		fmt.Printf("ℹ️  %s does not exist yet, a new database will be created.\n", absPath)
+	}
+
+	return absPath, nil
+}
+
+// embeddedMountArgs mounts the database file's directory (so journal and WAL files
+// next to it keep working) and tells the front-end which file to open.
+func embeddedMountArgs(database, dbFile string) []string {
+	args := []string{"-v", fmt.Sprintf("%s:/data", filepath.Dir(dbFile))}
+	if database == "sqlite" {
+		args = append(args, "-e", "SQLITE_DATABASE="+filepath.Base(dbFile))
+	}
+	fmt.Printf("Database file: %s (available in the container as /data/%s)\n", dbFile, filepath.Base(dbFile))
+	return args
+}
@@ -0,0 +1,192 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manifoldco/promptui"
+)
+
+// ExportFormats are the formats running services can be exported to
+var ExportFormats = []string{"compose", "env", "script", "stack", "k8s", "helm"}
+
+// exportFormatLabels describe ExportFormats in the interactive menu
+var exportFormatLabels = []string{
+	"Docker Compose",
+	"Docker Compose + .env (values out of the YAML)",
+	"Shell script of docker run commands",
+	"ContainDB stack file",
+	"Kubernetes manifests",
+	"Helm chart",
+}
+
+// ExportServices exports the selected ContainDB services and prints what was written
+func ExportServices(opts Docker.ExportOptions) {
+	fmt.Println("\n⚠️  IMPORTANT: The export functionality only exports container configurations, not the actual data.")
+	fmt.Println("   Even if you used data persistence during installation, the export only describes")
+	fmt.Println("   where data lives; the volumes on your current machine are not copied.")
+	fmt.Print("   For data backup, please use each database's native backup tools.\n\n")
+
+	result, err := Docker.ExportServices(opts)
+	if err != nil {
+		fmt.Println("❌ Export failed:", err)
+		return
+	}
+	path := result.Paths[0]
+
+	switch opts.Format {
+	case "env":
+		fmt.Println("✅ Docker Compose file created at:", path)
+		fmt.Println("✅ Environment values written to:", result.Paths[1], "(readable by you only)")
+		fmt.Println("   Keep .env next to the compose file; Compose reads it automatically.")
+	case "script":
+		fmt.Println("✅ Run script created at:", path)
+		fmt.Println("   Recreate the containers with:", path)
+	case "stack":
+		fmt.Println("✅ ContainDB stack file created at:", path)
+		fmt.Println("   Recreate the instances with: containDB --import", path)
+	case "helm":
+		fmt.Println("✅ Helm chart created successfully at:", path)
+		fmt.Println("   Install it with: helm install containdb", path)
+		fmt.Println("   values.yaml holds your credentials, keep it out of version control.")
+	case "k8s":
+		fmt.Println("✅ Kubernetes manifests created successfully at:", path)
+		fmt.Println("   Apply them with: kubectl apply -f", path)
+	default:
+		fmt.Println("✅ Docker Compose file created successfully at:", path)
+		fmt.Println("   This file contains only the configuration of your containers.")
+		if len(result.Paths) > 1 && len(result.Secrets) > 0 {
+			fmt.Printf("🔐 %d credential(s) moved to %s (readable by you only)\n", len(result.Secrets), result.Paths[1])
+		}
+	}
+	if opts.Redact && len(result.Secrets) > 0 {
+		fmt.Println("🔒 Credentials were left out. Set these variables (or add them to .env) before starting:")
+		for _, variable := range result.Secrets {
+			fmt.Println("   -", variable)
+		}
+	}
+	if opts.Format == "k8s" || opts.Format == "helm" {
+		fmt.Println("   Databases become StatefulSets with a PersistentVolumeClaim per data directory,")
+		fmt.Println("   tools become Deployments and credentials are stored in Secrets.")
+	}
+	warnIfInGitRepository(opts, result)
+}
+
+// warnIfInGitRepository warns when an export lands in a git repository,
+// where its credentials could be committed by accident
+func warnIfInGitRepository(opts Docker.ExportOptions, result Docker.ExportResult) {
+	path := result.Paths[0]
+	repo := Docker.GitRepository(path)
+	if repo == "" {
+		return
+	}
+	fmt.Printf("\n⚠️  %s is being written inside the git repository at %s\n", filepath.Base(path), repo)
+
+	credentials := path
+	switch opts.Format {
+	case "compose", "env":
+		credentials = ""
+		if len(result.Paths) > 1 && !opts.Redact {
+			credentials = result.Paths[1]
+		}
+	case "helm":
+		credentials = filepath.Join(path, "values.yaml")
+	}
+	if credentials != "" && !Docker.IsGitIgnored(credentials) {
+		fmt.Printf("   %s holds credentials and is not ignored; add it to .gitignore before committing.\n", filepath.Base(credentials))
+	}
+}
+
+// ExportInteractive asks for the format, the services and the output path of an export
+func ExportInteractive() {
+	formatPrompt := promptui.Select{
+		Label: "Export format",
+		Items: exportFormatLabels,
+	}
+	index, _, err := formatPrompt.Run()
+	if err != nil {
+		fmt.Println("\n⚠️ Cancelled")
+		return
+	}
+	opts := Docker.ExportOptions{Format: ExportFormats[index]}
+
+	running, err := Docker.ListRunningDatabases()
+	if err != nil {
+		fmt.Println("Error listing containers:", err)
+		return
+	}
+	if len(running) == 0 {
+		fmt.Println("No containers found running on ContainDB-Network")
+		return
+	}
+	selected, ok := multiSelect("Select the services to export", running)
+	if !ok || len(selected) == 0 {
+		fmt.Println("\n⚠️ Cancelled")
+		return
+	}
+	opts.Containers = selected
+
+	if opts.Format == "compose" || opts.Format == "env" {
+		opts.Redact = Docker.AskYesNo("Leave the credentials out of the export?")
+	}
+
+	opts.Output = tools.AskForInput("Output path", Docker.DefaultExportPath(opts.Format))
+	for _, path := range Docker.ExportPaths(opts) {
+		if _, err := os.Stat(path); err == nil {
+			if !Docker.AskYesNo(fmt.Sprintf("%s already exists. Back it up and write the new export?", filepath.Base(path))) {
+				fmt.Println("\n⚠️ Cancelled, nothing was overwritten")
+				return
+			}
+			opts.Backup = true
+		}
+	}
+
+	fmt.Println("Exporting the selected services...")
+	ExportServices(opts)
+}
+
+// multiSelect lets the user toggle items on and off, all selected at first.
+// Returns false when cancelled.
+func multiSelect(label string, items []string) ([]string, bool) {
+	selected := make([]bool, len(items))
+	for i := range selected {
+		selected[i] = true
+	}
+
+	cursor := 0
+	for {
+		var options []string
+		for i, item := range items {
+			mark := "⬜"
+			if selected[i] {
+				mark = "✅"
+			}
+			options = append(options, mark+" "+item)
+		}
+		options = append(options, "Done", "Cancel")
+
+		sel := promptui.Select{
+			Label: label + " (Enter toggles)",
+			Items: options,
+			Size:  min(len(options), 15),
+		}
+		index, _, err := sel.RunCursorAt(cursor, 0)
+		if err != nil || index == len(options)-1 {
+			return nil, false
+		}
+		if index == len(options)-2 {
+			var result []string
+			for i, item := range items {
+				if selected[i] {
+					result = append(result, item)
+				}
+			}
+			return result, true
+		}
+		selected[index] = !selected[index]
+		cursor = index
+	}
+}
@@ -11,6 +11,15 @@ import (
 
 // SelectFilePath provides a file selection prompt with path completion
 func SelectFilePath(label string, defaultPath string, extension string) (string, error) {
+	return selectPath(label, defaultPath, extension, false)
+}
+
+// SelectFileOrDirectory is SelectFilePath that also accepts a directory
+func SelectFileOrDirectory(label string, defaultPath string) (string, error) {
+	return selectPath(label, defaultPath, "", true)
+}
+
+func selectPath(label string, defaultPath string, extension string, allowDir bool) (string, error) {
 	currentPath := defaultPath
 
 	for {
@@ -56,6 +65,14 @@ func SelectFilePath(label string, defaultPath string, extension string) (string,
 					fmt.Println("  " + name)
 				}
 
+				if allowDir {
+					fmt.Print("Use this directory? (y/n): ")
+					var response string
+					fmt.Scanln(&response)
+					if strings.ToLower(response) == "y" {
+						return result, nil
+					}
+				}
 				continue
 			} else {
 				// It's a file and it exists, check if it has the right extension
@@ -0,0 +1,121 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// gatewayUsage lists the forms of `containdb gateway`
+const gatewayUsage = "Usage: containdb gateway up [--port 443] [--bind 127.0.0.1] | down | status"
+
+// defaultGatewayPort is 443, or 8443 when the runtime cannot publish privileged ports
+func defaultGatewayPort() string {
+	if Docker.IsRootless() {
+		return "8443"
+	}
+	return "443"
+}
+
+// GatewayCommand handles `containdb gateway up|down|status`
+func GatewayCommand(args []string) error {
+	switch args[0] {
+	case "up":
+		upFlags := flag.NewFlagSet("gateway up", flag.ExitOnError)
+		port := upFlags.String("port", defaultGatewayPort(), "host port the gateway serves HTTPS on")
+		bind := upFlags.String("bind", "", "host address the gateway binds to (default asks, 127.0.0.1 recommended)")
+		if positional := parseCommandArgs(upFlags, args[1:]); len(positional) > 0 {
+			return fmt.Errorf("%s", gatewayUsage)
+		}
+		return startGateway(*bind, *port)
+
+	case "down":
+		if err := tools.StopGateway(); err != nil {
+			return err
+		}
+		fmt.Println("✅ Gateway removed. Tools started behind it have no host port; reinstall them to publish one.")
+		return nil
+
+	case "status":
+		printGatewayStatus()
+		return nil
+	}
+	return fmt.Errorf("unknown gateway command %q\n%s", args[0], gatewayUsage)
+}
+
+func startGateway(bind, port string) error {
+	// A gateway being recreated holds the port it is checked for
+	if !tools.GatewayRunning() {
+		if check := Docker.CheckPort(port); check.Status == Docker.CheckFail {
+			return fmt.Errorf("port %s %s. %s", port, check.Message, check.Remediation)
+		}
+	}
+	address, err := tools.SelectBindAddress(bind)
+	if err != nil {
+		return err
+	}
+	if err := tools.StartGateway(address, port); err != nil {
+		return fmt.Errorf("failed to start the gateway: %v", err)
+	}
+
+	fmt.Printf("✅ Gateway started, serving https://<tool>.%s", tools.GatewayDomain)
+	if port != "443" {
+		fmt.Printf(":%s", port)
+	}
+	fmt.Println()
+	fmt.Println("   Tools installed from now on get no host port and are served by the gateway.")
+	fmt.Printf("   Trust the local CA to avoid browser warnings: containdb tls export ./containdb-ca.pem\n")
+	if Docker.IsRemoteEngine() {
+		fmt.Printf("ℹ️  *.%s resolves to this machine; tunnel to the gateway with `ssh -L %s:127.0.0.1:%s %s`.\n",
+			tools.GatewayDomain, port, port, Docker.EngineHost())
+	}
+	printGatewayStatus()
+	return nil
+}
+
+// printGatewayStatus prints whether the gateway runs and the URLs of the tools it serves
+func printGatewayStatus() {
+	if !tools.GatewayRunning() {
+		fmt.Println("Gateway is not running. Start it with: containdb gateway up")
+		return
+	}
+	served := tools.GatewayTools()
+	if len(served) == 0 {
+		fmt.Println("🌐 Gateway is running; no management tools are installed yet.")
+		return
+	}
+	fmt.Println("🌐 Gateway is serving:")
+	for _, tool := range served {
+		fmt.Printf("   %-32s %s\n", tool[0], tool[1])
+	}
+}
+
+// GatewayInteractive starts, inspects or removes the gateway from the menu
+func GatewayInteractive() {
+	prompt := promptui.Select{
+		Label: "HTTPS gateway for management tools",
+		Items: []string{"Start or recreate the gateway", "Show tool URLs", "Remove the gateway", "Back"},
+	}
+	_, choice, err := prompt.Run()
+	if err != nil || choice == "Back" {
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(choice, "Start"):
+		port := tools.AskForInput("Enter host port for HTTPS", defaultGatewayPort())
+		if err := startGateway("", port); err != nil {
+			fmt.Println("Error:", err)
+		}
+	case strings.HasPrefix(choice, "Show"):
+		printGatewayStatus()
+	case strings.HasPrefix(choice, "Remove"):
+		if err := GatewayCommand([]string{"down"}); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// HostCommand handles `containdb host add|list|remove|use`, which manages the
+// remote engines saved in the ContainDB config and picked with --host <name>
+func HostCommand(args []string) {
+	usage := "Usage: containdb host add <name> <url> [--tls-verify] [--cert-path ~/.docker/staging] | list | remove <name> | use <name|local>"
+	if len(args) == 0 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	config, err := Docker.LoadConfig()
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "add":
+		addFlags := flag.NewFlagSet("host add", flag.ExitOnError)
+		tlsVerify := addFlags.Bool("tls-verify", false, "verify the daemon's TLS certificate (tcp:// hosts)")
+		certPath := addFlags.String("cert-path", "", "directory with ca.pem, cert.pem and key.pem")
+		positional := parseCommandArgs(addFlags, args[1:])
+		if len(positional) != 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		name, hostURL := positional[0], positional[1]
+		if parsed, err := url.Parse(hostURL); err != nil || (parsed.Scheme != "tcp" && parsed.Scheme != "ssh") {
+			fmt.Println("Error: the URL must start with tcp:// or ssh://, e.g. ssh://deploy@staging-box")
+			os.Exit(1)
+		}
+		if _, exists := config.FindHost(name); exists {
+			fmt.Printf("Error: host '%s' already exists, remove it first\n", name)
+			os.Exit(1)
+		}
+		config.Hosts = append(config.Hosts, Docker.SavedHost{Name: name, URL: hostURL, TLSVerify: *tlsVerify, CertPath: *certPath})
+		saveConfigOrExit(config)
+		fmt.Printf("✅ Saved host '%s'. Use it with `containdb --host %s` or make it the default with `containdb host use %s`.\n", name, name, name)
+
+	case "list":
+		if len(config.Hosts) == 0 {
+			fmt.Println("No saved hosts. Add one with `containdb host add <name> <url>`.")
+			break
+		}
+		for _, host := range config.Hosts {
+			marker := "  "
+			if host.Name == config.DefaultHost {
+				marker = "* "
+			}
+			tls := ""
+			if host.TLSVerify {
+				tls = " (TLS verified)"
+			}
+			fmt.Printf("%s%-16s %s%s\n", marker, host.Name, host.URL, tls)
+		}
+
+	case "remove":
+		if len(args) != 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		var kept []Docker.SavedHost
+		for _, host := range config.Hosts {
+			if host.Name != args[1] {
+				kept = append(kept, host)
+			}
+		}
+		if len(kept) == len(config.Hosts) {
+			fmt.Printf("Error: no saved host named '%s'\n", args[1])
+			os.Exit(1)
+		}
+		config.Hosts = kept
+		if config.DefaultHost == args[1] {
+			config.DefaultHost = ""
+		}
+		saveConfigOrExit(config)
+		fmt.Printf("✅ Removed host '%s'.\n", args[1])
+
+	case "use":
+		if len(args) != 2 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		if args[1] == "local" {
+			config.DefaultHost = ""
+			saveConfigOrExit(config)
+			fmt.Println("✅ ContainDB uses the local engine by default.")
+			break
+		}
+		if _, exists := config.FindHost(args[1]); !exists {
+			fmt.Printf("Error: no saved host named '%s'\n", args[1])
+			os.Exit(1)
+		}
+		config.DefaultHost = args[1]
+		saveConfigOrExit(config)
+		fmt.Printf("✅ ContainDB uses '%s' by default. Pass --host or --context to override it.\n", args[1])
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func saveConfigOrExit(config Docker.Config) {
+	if err := Docker.SaveConfig(config); err != nil {
+		fmt.Println("Error saving config:", err)
+		os.Exit(1)
+	}
+}
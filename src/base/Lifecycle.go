@@ -0,0 +1,131 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"os"
+
+	"github.com/manifoldco/promptui"
+)
+
+// actionResults is the past tense of each lifecycle action, used in messages
+var actionResults = map[string]string{
+	"stop":    "stopped",
+	"start":   "started",
+	"restart": "restarted",
+	"pause":   "paused",
+	"unpause": "unpaused",
+}
+
+// selectInstance prompts for a ContainDB container. When action is not empty only
+// containers the action applies to are offered. Returns false when cancelled.
+func selectInstance(label, action string) (Docker.ContainerState, bool) {
+	return pickInstance(label, func(instance Docker.ContainerState) bool {
+		return action == "" || containsString(Docker.AvailableActions(instance.State), action)
+	})
+}
+
+// selectRunningInstance prompts for a running ContainDB container, for commands
+// that talk to the database. Returns false when cancelled.
+func selectRunningInstance(label string) (Docker.ContainerState, bool) {
+	return pickInstance(label, func(instance Docker.ContainerState) bool {
+		return instance.State == "running"
+	})
+}
+
+// pickInstance prompts for one of the ContainDB containers keep accepts
+func pickInstance(label string, keep func(Docker.ContainerState) bool) (Docker.ContainerState, bool) {
+	instances, err := Docker.ListContainDBInstances()
+	if err != nil {
+		fmt.Println("Error listing databases:", err)
+		return Docker.ContainerState{}, false
+	}
+
+	var candidates []Docker.ContainerState
+	for _, instance := range instances {
+		if keep(instance) {
+			candidates = append(candidates, instance)
+		}
+	}
+	if len(candidates) == 0 {
+		fmt.Println("No matching ContainDB containers found.")
+		return Docker.ContainerState{}, false
+	}
+
+	var items []string
+	for _, instance := range candidates {
+		items = append(items, fmt.Sprintf("%s (%s)", instance.Name, instance.Status))
+	}
+	items = append(items, "Exit")
+
+	sel := promptui.Select{
+		Label: label,
+		Items: items,
+	}
+	index, _, err := sel.Run()
+	if err != nil || index == len(items)-1 {
+		fmt.Println("\n⚠️ Cancelled")
+		return Docker.ContainerState{}, false
+	}
+	return candidates[index], true
+}
+
+// ManageDatabase lets the user stop, start, restart, pause or unpause a ContainDB
+// container without removing it
+func ManageDatabase() {
+	instance, ok := selectInstance("Select database to manage", "")
+	if !ok {
+		return
+	}
+
+	actions := append(Docker.AvailableActions(instance.State), "Back")
+	sel := promptui.Select{
+		Label: fmt.Sprintf("%s is %s. What do you want to do?", instance.Name, instance.State),
+		Items: actions,
+	}
+	_, action, err := sel.Run()
+	if err != nil || action == "Back" {
+		fmt.Println("\n⚠️ Cancelled")
+		return
+	}
+
+	runLifecycleAction(action, instance.Name)
+}
+
+// LifecycleCommand handles `containdb <stop|start|restart|pause|unpause> [container]`.
+// Without a container name the user picks one interactively.
+func LifecycleCommand(action string, args []string) {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		instance, ok := selectInstance(fmt.Sprintf("Select container to %s", action), action)
+		if !ok {
+			os.Exit(1)
+		}
+		name = instance.Name
+	}
+
+	if !runLifecycleAction(action, name) {
+		os.Exit(1)
+	}
+}
+
+func runLifecycleAction(action, name string) bool {
+	fmt.Printf("Running %s on %s...\n", action, name)
+	if err := Docker.ContainerLifecycle(action, name); err != nil {
+		fmt.Println("Error:", err)
+		return false
+	}
+	fmt.Printf("✅ %s %s successfully\n", name, actionResults[action])
+	return true
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
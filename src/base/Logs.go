@@ -0,0 +1,288 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// Log severities in increasing order; --level shows the given one and above
+var logLevels = []string{"debug", "info", "warn", "error", "fatal"}
+
+// logEntry is a log line after engine-aware parsing
+type logEntry struct {
+	level   string // one of logLevels, or "" when the line has no severity
+	text    string // line to print (structured logs are rendered compactly)
+	slow    bool   // slow query / slow log entry
+	authErr bool   // failed authentication attempt
+}
+
+// LogOptions controls which log lines are shown
+type LogOptions struct {
+	Follow bool
+	Since  string // passed to docker logs, e.g. "10m" or a timestamp
+	Level  string // minimum severity, empty for all lines
+}
+
+var (
+	// [2024-01-01T00:00:00,000][WARN ][o.o.n.Node] ... (OpenSearch / older Elasticsearch)
+	bracketLogPattern = regexp.MustCompile(`^\[[^\]]+\]\[(\w+)\s*\]`)
+	// 2024-01-01 00:00:00.000 UTC [42] ERROR:  ... (PostgreSQL)
+	postgresLogPattern = regexp.MustCompile(`\]\s+(DEBUG\d?|INFO|NOTICE|WARNING|ERROR|LOG|FATAL|PANIC|DETAIL|HINT|STATEMENT|CONTEXT):`)
+	// 2024-01-01T00:00:00Z 0 [Warning] [MY-010068] ... (MySQL / MariaDB)
+	mysqlLogPattern = regexp.MustCompile(`\[(System|Note|Warning|ERROR|Error)\]`)
+	// 1:M 01 Jan 2024 00:00:00.000 # ... (Redis and compatibles)
+	redisLogPattern = regexp.MustCompile(`^\d+:[XCSM] .+? [.\-*#] `)
+)
+
+// authFailureMarkers are substrings engines log when a login is rejected
+var authFailureMarkers = []string{
+	// PostgreSQL
+	"password authentication failed", "no pg_hba.conf entry",
+	// MySQL / MariaDB
+	"Access denied for user",
+	// MongoDB
+	"Authentication failed",
+	// Elasticsearch / OpenSearch
+	"failed to authenticate", "unable to authenticate", "authentication of",
+}
+
+// ViewLogs prints the logs of a container, filtered and highlighted according
+// to the engine that produced them
+func ViewLogs(name string, opts LogOptions) error {
+	if _, err := Docker.GetContainDBInstance(name); err != nil {
+		return err
+	}
+	if opts.Level != "" && levelRank(opts.Level) < 0 {
+		return fmt.Errorf("unknown level '%s' (expected one of: %s)", opts.Level, strings.Join(logLevels, ", "))
+	}
+
+	if opts.Follow {
+		// Let Ctrl+C simply end the stream instead of triggering the setup rollback
+		signal.Reset(os.Interrupt)
+	}
+
+	engine := Docker.DetectEngine(name)
+	minRank := levelRank(opts.Level)
+	lastLevel := ""
+
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+	slowTag := color.New(color.FgMagenta, color.Bold).Sprint("[SLOW]")
+	authTag := color.New(color.FgRed, color.Bold).Sprint("[AUTH]")
+
+	return Docker.StreamContainerLogs(name, opts.Follow, opts.Since, func(line string) {
+		entry := parseLogLine(engine, line)
+		// Lines without a severity (stack traces, DETAIL lines...) belong to the previous entry
+		if entry.level == "" {
+			entry.level = lastLevel
+		} else {
+			lastLevel = entry.level
+		}
+		if minRank > 0 && levelRank(entry.level) < minRank {
+			return
+		}
+
+		text := entry.text
+		switch entry.level {
+		case "error", "fatal":
+			text = red(text)
+		case "warn":
+			text = yellow(text)
+		case "debug":
+			text = dim(text)
+		}
+		if entry.slow {
+			text = slowTag + " " + text
+		}
+		if entry.authErr {
+			text = authTag + " " + text
+		}
+		fmt.Println(text)
+	})
+}
+
+func levelRank(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseLogLine extracts the severity of a log line and flags slow queries and
+// authentication failures, based on the log format of the engine
+func parseLogLine(engine, line string) logEntry {
+	entry := logEntry{text: line}
+
+	switch engine {
+	case "elasticsearch", "opensearch":
+		if strings.HasPrefix(line, "{") {
+			var record map[string]interface{}
+			if json.Unmarshal([]byte(line), &record) == nil {
+				level := stringField(record, "log.level", "level")
+				logger := stringField(record, "log.logger", "logger", "component")
+				entry.level = normalizeLevel(level)
+				entry.text = fmt.Sprintf("%s %-5s %s: %s", stringField(record, "@timestamp", "timestamp"),
+					strings.ToUpper(level), logger, stringField(record, "message"))
+				entry.slow = strings.Contains(logger, "slowlog")
+			}
+		} else if m := bracketLogPattern.FindStringSubmatch(line); m != nil {
+			entry.level = normalizeLevel(m[1])
+			entry.slow = strings.Contains(line, "slowlog")
+		}
+
+	case "mongodb":
+		if strings.HasPrefix(line, "{") {
+			var record struct {
+				T    map[string]interface{} `json:"t"`
+				S    string                 `json:"s"`
+				C    string                 `json:"c"`
+				Msg  string                 `json:"msg"`
+				Attr json.RawMessage        `json:"attr"`
+			}
+			if json.Unmarshal([]byte(line), &record) == nil {
+				entry.level = mongoLevel(record.S)
+				entry.text = fmt.Sprintf("%v %-2s %-8s %s", record.T["$date"], record.S, record.C, record.Msg)
+				if len(record.Attr) > 0 {
+					entry.text += " " + string(record.Attr)
+				}
+				entry.slow = record.Msg == "Slow query"
+			}
+		}
+
+	case "postgresql", "pgvector":
+		if m := postgresLogPattern.FindStringSubmatch(line); m != nil {
+			switch m[1] {
+			case "DETAIL", "HINT", "STATEMENT", "CONTEXT":
+				// continuation of the previous message, keeps its level
+			default:
+				entry.level = normalizeLevel(m[1])
+			}
+			entry.slow = strings.Contains(line, "duration:")
+		}
+
+	case "mysql", "mariadb":
+		if m := mysqlLogPattern.FindStringSubmatch(line); m != nil {
+			entry.level = normalizeLevel(m[1])
+		}
+
+	case "redis", "redis-stack", "valkey", "keydb":
+		if m := redisLogPattern.FindString(line); m != "" {
+			switch m[len(m)-2] {
+			case '.':
+				entry.level = "debug"
+			case '-', '*':
+				entry.level = "info"
+			case '#':
+				entry.level = "warn"
+			}
+		}
+	}
+
+	// Fall back to common severity words for engines without a known format
+	if entry.level == "" && engine != "postgresql" && engine != "pgvector" {
+		upper := strings.ToUpper(line)
+		for _, candidate := range []string{"FATAL", "ERROR", "WARN", "INFO", "DEBUG"} {
+			if strings.Contains(upper, candidate) {
+				entry.level = normalizeLevel(candidate)
+				break
+			}
+		}
+	}
+
+	for _, marker := range authFailureMarkers {
+		if strings.Contains(line, marker) {
+			entry.authErr = true
+			break
+		}
+	}
+
+	return entry
+}
+
+// normalizeLevel maps engine-specific severities onto logLevels
+func normalizeLevel(level string) string {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "TRACE", "DEBUG", "DEBUG1", "DEBUG2", "DEBUG3", "DEBUG4", "DEBUG5":
+		return "debug"
+	case "INFO", "LOG", "NOTICE", "NOTE", "SYSTEM":
+		return "info"
+	case "WARN", "WARNING", "DEPRECATION":
+		return "warn"
+	case "ERROR", "ERR":
+		return "error"
+	case "FATAL", "PANIC", "CRITICAL":
+		return "fatal"
+	}
+	return ""
+}
+
+// mongoLevel maps MongoDB's one-letter severities (F, E, W, I, D1-D5)
+func mongoLevel(s string) string {
+	switch {
+	case s == "F":
+		return "fatal"
+	case s == "E":
+		return "error"
+	case s == "W":
+		return "warn"
+	case s == "I":
+		return "info"
+	case strings.HasPrefix(s, "D"):
+		return "debug"
+	}
+	return ""
+}
+
+// stringField returns the first of keys present in a decoded JSON record
+func stringField(record map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := record[key]; ok {
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
+}
+
+// ViewLogsInteractive lets the user pick a container and log options from menus
+func ViewLogsInteractive() {
+	instance, ok := selectInstance("Select container to view logs", "")
+	if !ok {
+		return
+	}
+
+	levelPrompt := promptui.Select{
+		Label: "Which log lines do you want to see?",
+		Items: []string{"All", "Warnings and errors", "Errors only"},
+	}
+	_, levelChoice, err := levelPrompt.Run()
+	if err != nil {
+		fmt.Println("\n⚠️ Cancelled")
+		return
+	}
+
+	opts := LogOptions{}
+	switch levelChoice {
+	case "Warnings and errors":
+		opts.Level = "warn"
+	case "Errors only":
+		opts.Level = "error"
+	}
+	opts.Since = strings.TrimSpace(tools.AskForInput("Show logs since (e.g. 10m, 2h; empty for all)", ""))
+	opts.Follow = Docker.AskYesNo("Follow new log lines? (Ctrl+C to stop)")
+
+	if err := ViewLogs(instance.Name, opts); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
@@ -0,0 +1,30 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PortRole is a group of secondary ports serving one purpose, published only
+// when the user asks for it
+type PortRole struct {
+	Role  string
+	Ports []string
+}
+
+// publishArgs returns the -p arguments publishing containerPort on hostPort of address
+func publishArgs(address, hostPort, containerPort string) []string {
+	if strings.Contains(address, ":") {
+		address = "[" + address + "]"
+	}
+	return []string{"-p", fmt.Sprintf("%s:%s:%s", address, hostPort, containerPort)}
+}
+
+// rolePorts flattens the ports of port roles
+func rolePorts(roles []PortRole) []string {
+	var ports []string
+	for _, role := range roles {
+		ports = append(ports, role.Ports...)
+	}
+	return ports
+}
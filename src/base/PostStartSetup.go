@@ -0,0 +1,103 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// postStartSetup finishes the configuration of engines that can only be
+// initialized over their REST API once the container is up.
+func postStartSetup(database, containerName, adminUser, adminPass string) error {
+	switch database {
+	case "couchdb":
+		return setupCouchDB(containerName, adminUser, adminPass)
+	case "couchbase":
+		return setupCouchbase(containerName, adminUser, adminPass)
+	case "milvus":
+		if adminPass != "" {
+			return setupMilvus(containerName, adminPass)
+		}
+	}
+	return nil
+}
+
+// setupCouchDB turns a fresh CouchDB container into a single-node cluster, which
+// also creates the _users, _replicator and _global_changes system databases.
+func setupCouchDB(containerName, adminUser, adminPass string) error {
+	fmt.Println("Configuring CouchDB as a single-node cluster...")
+	body := fmt.Sprintf(`{"action":"enable_single_node","username":%q,"password":%q,"bind_address":"0.0.0.0","port":5984,"singlenode":true}`,
+		adminUser, adminPass)
+	_, err := Docker.NetworkCurl(
+		"-u", adminUser+":"+adminPass,
+		"-X", "POST",
+		"-H", "Content-Type: application/json",
+		"-d", body,
+		fmt.Sprintf("http://%s:5984/_cluster_setup", containerName),
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Println("✅ CouchDB single-node setup completed.")
+	return nil
+}
+
+// setupCouchbase initializes a new Couchbase Server node: enables the core
+// services, sets memory quotas and creates the administrator account.
+func setupCouchbase(containerName, adminUser, adminPass string) error {
+	fmt.Println("Initializing Couchbase cluster (this can take a minute)...")
+	baseURL := fmt.Sprintf("http://%s:8091", containerName)
+
+	steps := []struct {
+		description string
+		args        []string
+	}{
+		{"Enabling services", []string{"-X", "POST", baseURL + "/node/controller/setupServices",
+			"-d", "services=kv,n1ql,index,fts"}},
+		{"Setting memory quotas", []string{"-X", "POST", baseURL + "/pools/default",
+			"-d", "memoryQuota=512", "-d", "indexMemoryQuota=256", "-d", "ftsMemoryQuota=256"}},
+		{"Creating administrator", []string{"-X", "POST", baseURL + "/settings/web",
+			"-d", "port=SAME", "--data-urlencode", "username=" + adminUser, "--data-urlencode", "password=" + adminPass}},
+		{"Configuring index storage", []string{"-u", adminUser + ":" + adminPass, "-X", "POST", baseURL + "/settings/indexes",
+			"-d", "storageMode=plasma"}},
+	}
+
+	for _, step := range steps {
+		fmt.Printf("- %s...\n", step.description)
+		if _, err := Docker.NetworkCurl(step.args...); err != nil {
+			return fmt.Errorf("%s: %v", step.description, err)
+		}
+	}
+
+	fmt.Println("✅ Couchbase cluster initialized.")
+	return nil
+}
+
+// setupMilvus replaces the default password of Milvus' root user, which
+// authentication (turned on through user.yaml) starts with
+func setupMilvus(containerName, password string) error {
+	fmt.Println("Setting the Milvus root password (Milvus can take a minute to start)...")
+	body := fmt.Sprintf(`{"userName":"root","password":%q,"newPassword":%q}`, milvusDefaultPassword, password)
+	var out string
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		out, err = Docker.NetworkCurl(
+			"-X", "POST",
+			"-H", "Authorization: Bearer root:"+milvusDefaultPassword,
+			"-H", "Content-Type: application/json",
+			"-d", body,
+			fmt.Sprintf("http://%s:19530/v2/vectordb/users/update_password", containerName),
+		)
+		// The REST API answers 200 with a non-zero code on failure
+		if err == nil && strings.Contains(out, `"code":0`) {
+			fmt.Println("✅ Milvus root password set.")
+			return nil
+		}
+		time.Sleep(10 * time.Second)
+	}
+	if err == nil {
+		err = fmt.Errorf("%s", out)
+	}
+	return err
+}
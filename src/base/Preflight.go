@@ -0,0 +1,81 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+)
+
+// EngineRequirements are the host resources an engine needs to start reliably
+type EngineRequirements struct {
+	RAMGB       float64 // host RAM
+	DataGB      float64 // free disk for initial data, on top of the image
+	MaxMapCount bool    // needs vm.max_map_count >= 262144 (Elasticsearch, OpenSearch)
+}
+
+// runPreflight checks the host before installing an engine and prints the
+// report. Every port is optional to publish, so a conflict only warns.
+// Returns false when a check failed and the user chose not to continue.
+func runPreflight(database, image, primaryPort string, secondaryPorts []string, req EngineRequirements) bool {
+	fmt.Printf("\n🔎 Preflight checks for %s\n", database)
+
+	results := []Docker.CheckResult{Docker.CheckRAM(req.RAMGB), diskCheck(image, req.DataGB)}
+	if req.MaxMapCount {
+		results = append(results, Docker.CheckMaxMapCount())
+	}
+
+	primary := Docker.CheckPort(primaryPort)
+	if primary.Status == Docker.CheckFail {
+		primary.Status = Docker.CheckWarn
+		primary.Remediation = "Choose a custom host port when asked, or do not publish the port"
+	}
+	results = append(results, primary)
+	for _, port := range secondaryPorts {
+		check := Docker.CheckPort(port)
+		if check.Status == Docker.CheckFail {
+			check.Status = Docker.CheckWarn
+			check.Remediation = "Do not publish the port when asked"
+		}
+		results = append(results, check)
+	}
+	results = append(results, Docker.CheckImageArch(image))
+
+	printCheckResults(results)
+
+	if Docker.HasFailure(results) {
+		return Docker.AskYesNo("Some preflight checks failed. Continue anyway?")
+	}
+	return true
+}
+
+// unknownImageGB is assumed for an image whose manifest cannot be read
+const unknownImageGB = 2
+
+// diskCheck verifies the free disk holds the image still to pull and dataGB of data
+func diskCheck(image string, dataGB float64) Docker.CheckResult {
+	imageGB, known := Docker.ImagePullGB(image)
+	detail := fmt.Sprintf("%s: %.2f GB to pull, %.2f GB for data", image, imageGB, dataGB)
+	switch {
+	case !known:
+		imageGB = unknownImageGB
+		detail = fmt.Sprintf("%s: size unknown, assuming %d GB, %.2f GB for data", image, unknownImageGB, dataGB)
+	case imageGB == 0:
+		detail = fmt.Sprintf("%s: already pulled, %.2f GB for data", image, dataGB)
+	}
+	result := Docker.CheckDiskSpace(imageGB + dataGB)
+	result.Details = append(result.Details, detail)
+	return result
+}
+
+// printCheckResults prints one line per check with its remediation hint and details
+func printCheckResults(results []Docker.CheckResult) {
+	icons := map[string]string{Docker.CheckPass: "✅", Docker.CheckWarn: "⚠️ ", Docker.CheckFail: "❌"}
+	for _, result := range results {
+		fmt.Printf("  %s %s: %s\n", icons[result.Status], result.Name, result.Message)
+		if result.Remediation != "" && result.Status != Docker.CheckPass {
+			fmt.Printf("     → %s\n", result.Remediation)
+		}
+		for _, detail := range result.Details {
+			fmt.Printf("     │ %s\n", detail)
+		}
+	}
+}
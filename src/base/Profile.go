@@ -0,0 +1,714 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// profileStatePath is where `profile on` saves the original server settings,
+// inside the profiled container so the state goes away with the container
+const profileStatePath = "/tmp/containdb-profile.json"
+
+// Log files MySQL and MariaDB write to while profiling is on
+const (
+	mysqlSlowLogPath    = "/tmp/containdb-slow.log"
+	mysqlGeneralLogPath = "/tmp/containdb-general.log"
+)
+
+// ProfileOptions controls what `profile on` records and what `profile tail` shows
+type ProfileOptions struct {
+	ThresholdMs int    // queries slower than this are recorded
+	All         bool   // record every query (general log), not only slow ones
+	Since       string // how far back tail starts, e.g. "10m"
+}
+
+// profileState is the original configuration of a profiled container, restored
+// by `profile off`
+type profileState struct {
+	Engine   string            `json:"engine"`
+	All      bool              `json:"all"`
+	Settings map[string]string `json:"settings"`
+}
+
+// ProfileCommand handles `containdb profile <container> on|off|tail`
+func ProfileCommand(name, action string, opts ProfileOptions) error {
+	instance, err := Docker.GetContainDBInstance(name)
+	if err != nil {
+		return err
+	}
+	if instance.State != "running" {
+		return fmt.Errorf("%s is %s, start it first with `containdb start %s`", name, instance.State, name)
+	}
+
+	engine := profileEngine(Docker.DetectEngine(name))
+	if engine == "" {
+		return fmt.Errorf("query profiling is supported for PostgreSQL, MySQL, MariaDB, MongoDB and Redis-compatible containers only")
+	}
+
+	switch action {
+	case "on":
+		return profileOn(name, engine, opts)
+	case "off":
+		return profileOff(name)
+	case "tail":
+		return profileTail(name, opts)
+	}
+	return fmt.Errorf("unknown action '%s' (expected on, off or tail)", action)
+}
+
+// profileEngine groups engines that share the same profiling settings
+func profileEngine(engine string) string {
+	switch engine {
+	case "postgresql", "pgvector":
+		return "postgresql"
+	case "mysql", "mariadb", "mongodb":
+		return engine
+	case "redis", "redis-stack", "valkey", "keydb":
+		return "redis"
+	}
+	return ""
+}
+
+func profileOn(name, engine string, opts ProfileOptions) error {
+	// Keep the settings saved by an earlier `on`, they are the real originals
+	state, err := loadProfileState(name)
+	if err != nil {
+		state = &profileState{Engine: engine, Settings: map[string]string{}}
+	}
+	state.All = opts.All
+
+	switch engine {
+	case "postgresql":
+		err = postgresProfileOn(name, state, opts)
+	case "mysql", "mariadb":
+		err = mysqlProfileOn(name, state, opts)
+	case "mongodb":
+		err = mongoProfileOn(name, state, opts)
+	case "redis":
+		err = redisProfileOn(name, state, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if err := saveProfileState(name, state); err != nil {
+		return err
+	}
+
+	if opts.All {
+		fmt.Printf("✅ Query logging enabled on %s (all queries)\n", name)
+	} else {
+		fmt.Printf("✅ Slow query logging enabled on %s (queries slower than %d ms)\n", name, opts.ThresholdMs)
+	}
+	fmt.Printf("   Watch it with `containdb profile %s tail`, restore the original settings with `containdb profile %s off`\n", name, name)
+	return nil
+}
+
+func profileOff(name string) error {
+	state, err := loadProfileState(name)
+	if err != nil {
+		return fmt.Errorf("profiling is not enabled on %s", name)
+	}
+
+	switch state.Engine {
+	case "postgresql":
+		err = postgresProfileOff(name, state)
+	case "mysql", "mariadb":
+		err = mysqlProfileOff(name, state)
+	case "mongodb":
+		err = mongoProfileOff(name, state)
+	case "redis":
+		err = redisProfileOff(name, state)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, _ = Docker.ExecInContainer(name, nil, "rm", "-f", profileStatePath, mysqlSlowLogPath, mysqlGeneralLogPath)
+	fmt.Printf("✅ Profiling disabled on %s, original settings restored\n", name)
+	return nil
+}
+
+func profileTail(name string, opts ProfileOptions) error {
+	state, err := loadProfileState(name)
+	if err != nil {
+		return fmt.Errorf("profiling is not enabled on %s, run `containdb profile %s on` first", name, name)
+	}
+
+	// Let Ctrl+C simply end the stream instead of triggering the setup rollback
+	signal.Reset(os.Interrupt)
+	fmt.Printf("📈 Recorded queries of %s (Ctrl+C to stop)\n", name)
+
+	switch state.Engine {
+	case "postgresql":
+		return postgresProfileTail(name, opts)
+	case "mysql", "mariadb":
+		return mysqlProfileTail(name, state)
+	case "mongodb":
+		return mongoProfileTail(name, opts)
+	case "redis":
+		return redisProfileTail(name)
+	}
+	return nil
+}
+
+func loadProfileState(name string) (*profileState, error) {
+	out, err := Docker.ExecInContainer(name, nil, "cat", profileStatePath)
+	if err != nil {
+		return nil, err
+	}
+	var state profileState
+	if err := json.Unmarshal([]byte(out), &state); err != nil {
+		return nil, err
+	}
+	if state.Settings == nil {
+		state.Settings = map[string]string{}
+	}
+	return &state, nil
+}
+
+func saveProfileState(name string, state *profileState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return Docker.WriteFileInContainer(name, profileStatePath, string(data))
+}
+
+// saveOriginal records the original value of a setting unless an earlier
+// `profile on` already did
+func (s *profileState) saveOriginal(key, value string) {
+	if _, ok := s.Settings[key]; !ok {
+		s.Settings[key] = value
+	}
+}
+
+// printQuery prints a recorded query with its duration highlighted
+func printQuery(duration, query string) {
+	tag := color.New(color.FgMagenta, color.Bold).Sprintf("[%s]", duration)
+	fmt.Println(tag, query)
+}
+
+// ---- PostgreSQL ----
+
+var postgresProfileSettings = []string{"log_min_duration_statement", "log_statement", "shared_preload_libraries"}
+
+var (
+	postgresDurationPattern  = regexp.MustCompile(`duration: ([\d.]+) ms\s+(?:statement|execute [^:]*|parse [^:]*|bind [^:]*): (.*)$`)
+	postgresStatementPattern = regexp.MustCompile(`LOG:\s+statement: (.*)$`)
+)
+
+func postgresQuery(name, sql string) (string, error) {
+	return Docker.ExecInContainer(name, nil, append(postgresClient(name), "-XAtq", "-F", "\t", "-c", sql)...)
+}
+
+// postgresClient returns the psql command connecting to the container's own database
+func postgresClient(name string) []string {
+	user := Docker.GetContainerEnv(name, "POSTGRES_USER")
+	if user == "" {
+		user = "postgres"
+	}
+	database := Docker.GetContainerEnv(name, "POSTGRES_DB")
+	if database == "" {
+		database = user
+	}
+	return []string{"psql", "-U", user, "-d", database}
+}
+
+func postgresProfileOn(name string, state *profileState, opts ProfileOptions) error {
+	// Settings that were not set with ALTER SYSTEM are saved as "" and reset on `off`
+	out, err := postgresQuery(name, fmt.Sprintf(
+		"SELECT name, setting, coalesce(sourcefile, '') FROM pg_settings WHERE name IN ('%s')",
+		strings.Join(postgresProfileSettings, "','")))
+	if err != nil {
+		return fmt.Errorf("failed to read PostgreSQL settings: %v", err)
+	}
+	current := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 {
+			continue
+		}
+		current[parts[0]] = parts[1]
+		if strings.HasSuffix(parts[2], "postgresql.auto.conf") {
+			state.saveOriginal(parts[0], parts[1])
+		} else {
+			state.saveOriginal(parts[0], "")
+		}
+	}
+
+	statements := []string{fmt.Sprintf("ALTER SYSTEM SET log_min_duration_statement = %d", opts.ThresholdMs)}
+	if opts.All {
+		statements = append(statements, "ALTER SYSTEM SET log_statement = 'all'")
+	} else {
+		statements = append(statements, "ALTER SYSTEM SET log_statement = 'none'")
+	}
+	for _, statement := range append(statements, "SELECT pg_reload_conf()") {
+		if _, err := postgresQuery(name, statement); err != nil {
+			return fmt.Errorf("failed to enable query logging: %v", err)
+		}
+	}
+
+	// pg_stat_statements has to be preloaded, which needs a restart
+	if !strings.Contains(current["shared_preload_libraries"], "pg_stat_statements") {
+		fmt.Println("ℹ️  pg_stat_statements must be preloaded by PostgreSQL, which requires a restart.")
+		if !Docker.AskYesNo(fmt.Sprintf("Restart %s now to enable pg_stat_statements?", name)) {
+			return nil
+		}
+		libraries := "pg_stat_statements"
+		if current["shared_preload_libraries"] != "" {
+			libraries = current["shared_preload_libraries"] + "," + libraries
+		}
+		if _, err := postgresQuery(name, fmt.Sprintf("ALTER SYSTEM SET shared_preload_libraries = %s", sqlQuote(libraries))); err != nil {
+			return fmt.Errorf("failed to preload pg_stat_statements: %v", err)
+		}
+		if err := restartAndWait(name, []string{"pg_isready", "-q"}); err != nil {
+			return err
+		}
+	}
+
+	exists, _ := postgresQuery(name, "SELECT count(*) FROM pg_extension WHERE extname = 'pg_stat_statements'")
+	state.saveOriginal("pg_stat_statements_extension", exists)
+	if _, err := postgresQuery(name, "CREATE EXTENSION IF NOT EXISTS pg_stat_statements"); err != nil {
+		return fmt.Errorf("failed to create pg_stat_statements: %v", err)
+	}
+	_, _ = postgresQuery(name, "SELECT pg_stat_statements_reset()")
+	return nil
+}
+
+func postgresProfileOff(name string, state *profileState) error {
+	for _, setting := range postgresProfileSettings {
+		value, ok := state.Settings[setting]
+		if !ok {
+			continue
+		}
+		statement := fmt.Sprintf("ALTER SYSTEM RESET %s", setting)
+		if value != "" {
+			statement = fmt.Sprintf("ALTER SYSTEM SET %s = %s", setting, sqlQuote(value))
+		}
+		if _, err := postgresQuery(name, statement); err != nil {
+			return fmt.Errorf("failed to restore %s: %v", setting, err)
+		}
+	}
+	if state.Settings["pg_stat_statements_extension"] == "0" {
+		_, _ = postgresQuery(name, "DROP EXTENSION IF EXISTS pg_stat_statements")
+	}
+	if _, err := postgresQuery(name, "SELECT pg_reload_conf()"); err != nil {
+		return fmt.Errorf("failed to reload configuration: %v", err)
+	}
+
+	current, _ := postgresQuery(name, "SHOW shared_preload_libraries")
+	if original, ok := state.Settings["shared_preload_libraries"]; ok && original == "" && strings.Contains(current, "pg_stat_statements") {
+		fmt.Printf("ℹ️  pg_stat_statements stays loaded until %s is restarted.\n", name)
+	}
+	return nil
+}
+
+func postgresProfileTail(name string, opts ProfileOptions) error {
+	if ok, _ := postgresQuery(name, "SELECT to_regclass('pg_stat_statements') IS NOT NULL"); ok == "t" {
+		out, err := postgresQuery(name, "SELECT calls, round(mean_exec_time::numeric, 2), "+
+			"left(regexp_replace(query, '\\s+', ' ', 'g'), 120) FROM pg_stat_statements "+
+			"ORDER BY total_exec_time DESC LIMIT 10")
+		if err == nil && out != "" {
+			fmt.Println("\nTop queries by total time (pg_stat_statements):")
+			for _, line := range strings.Split(out, "\n") {
+				parts := strings.SplitN(line, "\t", 3)
+				if len(parts) == 3 {
+					printQuery(parts[1]+" ms avg", fmt.Sprintf("%s calls  %s", parts[0], parts[2]))
+				}
+			}
+			fmt.Println()
+		}
+	}
+
+	return Docker.StreamContainerLogs(name, true, opts.Since, func(line string) {
+		if m := postgresDurationPattern.FindStringSubmatch(line); m != nil {
+			printQuery(m[1]+" ms", m[2])
+		} else if m := postgresStatementPattern.FindStringSubmatch(line); m != nil {
+			printQuery("query", m[1])
+		}
+	})
+}
+
+// restartAndWait restarts a container and waits until ready succeeds inside it
+func restartAndWait(name string, ready []string) error {
+	fmt.Printf("Restarting %s...\n", name)
+	if err := Docker.ContainerLifecycle("restart", name); err != nil {
+		return err
+	}
+	for i := 0; i < 30; i++ {
+		if _, err := Docker.ExecInContainer(name, nil, ready...); err == nil {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("%s did not become ready after restart", name)
+}
+
+func sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// ---- MySQL / MariaDB ----
+
+var mysqlProfileSettings = []string{"slow_query_log", "long_query_time", "slow_query_log_file", "general_log", "general_log_file", "log_output"}
+
+var (
+	mysqlQueryTimePattern = regexp.MustCompile(`^# Query_time: ([\d.]+)\s+Lock_time: [\d.]+\s+Rows_sent: (\d+)\s+Rows_examined: (\d+)`)
+	mysqlGeneralPattern   = regexp.MustCompile(`\s(\d+) (Query|Execute|Connect|Quit|Init DB)\t(.*)$`)
+)
+
+func mysqlQuery(name, sql string) (string, error) {
+	client, env := mysqlClient(name)
+	return Docker.ExecInContainer(name, env, client, "-uroot", "-N", "-B", "-e", sql)
+}
+
+// mysqlClient returns the client binary of a MySQL or MariaDB container and
+// the environment passing it the root password
+func mysqlClient(name string) (string, []string) {
+	client := "mysql"
+	password := Docker.GetContainerEnv(name, "MYSQL_ROOT_PASSWORD")
+	if Docker.DetectEngine(name) == "mariadb" {
+		// Recent MariaDB images no longer ship the mysql client alias
+		client = "mariadb"
+		if p := Docker.GetContainerEnv(name, "MARIADB_ROOT_PASSWORD"); p != "" {
+			password = p
+		}
+	}
+	return client, []string{"MYSQL_PWD=" + password}
+}
+
+func mysqlProfileOn(name string, state *profileState, opts ProfileOptions) error {
+	var selects []string
+	for _, setting := range mysqlProfileSettings {
+		selects = append(selects, "@@GLOBAL."+setting)
+	}
+	out, err := mysqlQuery(name, "SELECT "+strings.Join(selects, ", "))
+	if err != nil {
+		return fmt.Errorf("failed to read server settings: %v", err)
+	}
+	values := strings.Split(out, "\t")
+	if len(values) != len(mysqlProfileSettings) {
+		return fmt.Errorf("unexpected server settings: %s", out)
+	}
+	for i, setting := range mysqlProfileSettings {
+		state.saveOriginal(setting, values[i])
+	}
+
+	statements := []string{
+		"SET GLOBAL log_output = 'FILE'",
+		fmt.Sprintf("SET GLOBAL slow_query_log_file = '%s'", mysqlSlowLogPath),
+		fmt.Sprintf("SET GLOBAL long_query_time = %.3f", float64(opts.ThresholdMs)/1000),
+		"SET GLOBAL slow_query_log = ON",
+	}
+	if opts.All {
+		statements = append(statements,
+			fmt.Sprintf("SET GLOBAL general_log_file = '%s'", mysqlGeneralLogPath),
+			"SET GLOBAL general_log = ON")
+	} else {
+		statements = append(statements, "SET GLOBAL general_log = OFF")
+	}
+	if _, err := mysqlQuery(name, strings.Join(statements, "; ")); err != nil {
+		return fmt.Errorf("failed to enable query logging: %v", err)
+	}
+	return nil
+}
+
+func mysqlProfileOff(name string, state *profileState) error {
+	var statements []string
+	for _, setting := range mysqlProfileSettings {
+		value, ok := state.Settings[setting]
+		if !ok {
+			continue
+		}
+		switch setting {
+		case "slow_query_log", "general_log", "long_query_time":
+			statements = append(statements, fmt.Sprintf("SET GLOBAL %s = %s", setting, value))
+		default:
+			statements = append(statements, fmt.Sprintf("SET GLOBAL %s = %s", setting, sqlQuote(value)))
+		}
+	}
+	if _, err := mysqlQuery(name, strings.Join(statements, "; ")); err != nil {
+		return fmt.Errorf("failed to restore server settings: %v", err)
+	}
+	return nil
+}
+
+func mysqlProfileTail(name string, state *profileState) error {
+	files := []string{mysqlSlowLogPath}
+	if state.All {
+		files = append(files, mysqlGeneralLogPath)
+	}
+
+	slowLog := false
+	duration, rows := "", ""
+	return Docker.StreamExec(name, append([]string{"tail", "-n", "50", "-F"}, files...), func(line string) {
+		switch {
+		case strings.HasPrefix(line, "==> "):
+			slowLog = strings.Contains(line, mysqlSlowLogPath)
+		case !slowLog:
+			if m := mysqlGeneralPattern.FindStringSubmatch(line); m != nil && m[3] != "" {
+				printQuery(strings.ToLower(m[2]), m[3])
+			}
+		case strings.HasPrefix(line, "# Query_time:"):
+			if m := mysqlQueryTimePattern.FindStringSubmatch(line); m != nil {
+				seconds, _ := strconv.ParseFloat(m[1], 64)
+				duration = fmt.Sprintf("%.1f ms", seconds*1000)
+				rows = fmt.Sprintf("(rows sent %s, examined %s)", m[2], m[3])
+			}
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "SET timestamp="),
+			strings.HasPrefix(line, "use "), duration == "":
+			// headers, session setup and the file banner
+		default:
+			printQuery(duration, line+" "+rows)
+			duration = ""
+		}
+	})
+}
+
+// ---- MongoDB ----
+
+func mongoEval(name, script string) (string, error) {
+	return Docker.ExecInContainer(name, nil, append(mongoShell(name), "--eval", script)...)
+}
+
+// mongoShell returns the mongosh command, authenticated as the root user when there is one
+func mongoShell(name string) []string {
+	command := []string{"mongosh", "--quiet"}
+	if user := Docker.GetContainerEnv(name, "MONGO_INITDB_ROOT_USERNAME"); user != "" {
+		command = append(command, "-u", user, "-p", Docker.GetContainerEnv(name, "MONGO_INITDB_ROOT_PASSWORD"),
+			"--authenticationDatabase", "admin")
+	}
+	if ca := Docker.GetContainerEnv(name, Docker.TLSCAEnv); ca != "" {
+		command = append(command, "--tls", "--tlsCAFile", ca)
+	}
+	return command
+}
+
+func mongoProfileOn(name string, state *profileState, opts ProfileOptions) error {
+	// Level 1 profiles operations slower than slowms, level 2 every operation.
+	// slowms is server wide, the level is per database.
+	level, slowms := 1, opts.ThresholdMs
+	if opts.All {
+		level, slowms = 2, 0
+	}
+	script := fmt.Sprintf(`
+const result = { slowms: db.getSiblingDB("admin").runCommand({ profile: -1 }).slowms, databases: {} };
+db.adminCommand({ listDatabases: 1 }).databases
+  .map(d => d.name)
+  .filter(n => !["admin", "local", "config"].includes(n))
+  .forEach(n => { result.databases[n] = db.getSiblingDB(n).setProfilingLevel(%d, { slowms: %d }).was; });
+db.getSiblingDB("admin").runCommand({ profile: 0, slowms: %d });
+print(JSON.stringify(result));`, level, slowms, slowms)
+
+	out, err := mongoEval(name, script)
+	if err != nil {
+		return fmt.Errorf("failed to enable the profiler: %v", err)
+	}
+	var result struct {
+		Slowms    int            `json:"slowms"`
+		Databases map[string]int `json:"databases"`
+	}
+	if err := json.Unmarshal([]byte(lastLine(out)), &result); err != nil {
+		return fmt.Errorf("unexpected profiler output: %s", out)
+	}
+
+	state.saveOriginal("slowms", strconv.Itoa(result.Slowms))
+	for database, was := range result.Databases {
+		state.saveOriginal("db:"+database, strconv.Itoa(was))
+	}
+	fmt.Println("ℹ️  Databases created from now on log slow operations but are not profiled to system.profile.")
+	return nil
+}
+
+func mongoProfileOff(name string, state *profileState) error {
+	slowms := state.Settings["slowms"]
+	if slowms == "" {
+		slowms = "100"
+	}
+	script := fmt.Sprintf(`db.getSiblingDB("admin").runCommand({ profile: 0, slowms: %s });`, slowms)
+	for key, was := range state.Settings {
+		if database, ok := strings.CutPrefix(key, "db:"); ok {
+			script += fmt.Sprintf("\ndb.getSiblingDB(%q).setProfilingLevel(%s, { slowms: %s });", database, was, slowms)
+		}
+	}
+	if _, err := mongoEval(name, script); err != nil {
+		return fmt.Errorf("failed to restore the profiler settings: %v", err)
+	}
+	return nil
+}
+
+func mongoProfileTail(name string, opts ProfileOptions) error {
+	return Docker.StreamContainerLogs(name, true, opts.Since, func(line string) {
+		var record struct {
+			Msg  string `json:"msg"`
+			Attr struct {
+				Type           string          `json:"type"`
+				Namespace      string          `json:"ns"`
+				Command        json.RawMessage `json:"command"`
+				PlanSummary    string          `json:"planSummary"`
+				DurationMillis int             `json:"durationMillis"`
+			} `json:"attr"`
+		}
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &record) != nil || record.Msg != "Slow query" {
+			return
+		}
+		query := fmt.Sprintf("%s %s %s", record.Attr.Namespace, record.Attr.Type, string(record.Attr.Command))
+		if record.Attr.PlanSummary != "" {
+			query += " plan=" + record.Attr.PlanSummary
+		}
+		printQuery(fmt.Sprintf("%d ms", record.Attr.DurationMillis), query)
+	})
+}
+
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return lines[len(lines)-1]
+}
+
+// ---- Redis and compatibles ----
+
+// redisSlowlogScript flattens SLOWLOG GET into one tab separated line per entry:
+// id, unix time, duration in microseconds and the command
+const redisSlowlogScript = `local out = {}
+for _, e in ipairs(redis.call('SLOWLOG', 'GET', '128')) do
+  table.insert(out, e[1] .. '\t' .. e[2] .. '\t' .. e[3] .. '\t' .. table.concat(e[4], ' '))
+end
+return out`
+
+func redisCommand(name string, args ...string) (string, error) {
+	out, err := Docker.ExecInContainer(name, redisAuthEnv(name), append(append(redisCLI(name), "--raw"), args...)...)
+	if err == nil && strings.HasPrefix(out, "ERR") {
+		return out, fmt.Errorf("%s", out)
+	}
+	return out, err
+}
+
+// redisCLI returns the command line client shipped with a Redis-compatible
+// container, connecting over TLS when the container serves it
+func redisCLI(name string) []string {
+	client := "redis-cli"
+	switch Docker.DetectEngine(name) {
+	case "valkey":
+		client = "valkey-cli"
+	case "keydb":
+		client = "keydb-cli"
+	}
+	if ca := Docker.GetContainerEnv(name, Docker.TLSCAEnv); ca != "" {
+		return []string{client, "--tls", "--cacert", ca}
+	}
+	return []string{client}
+}
+
+func redisProfileOn(name string, state *profileState, opts ProfileOptions) error {
+	for _, setting := range []string{"slowlog-log-slower-than", "slowlog-max-len"} {
+		out, err := redisCommand(name, "CONFIG", "GET", setting)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", setting, err)
+		}
+		if lines := strings.Split(out, "\n"); len(lines) == 2 {
+			state.saveOriginal(setting, lines[1])
+		}
+	}
+
+	threshold := strconv.Itoa(opts.ThresholdMs * 1000) // microseconds
+	if opts.All {
+		threshold = "0"
+	}
+	for _, args := range [][]string{
+		{"CONFIG", "SET", "slowlog-log-slower-than", threshold},
+		{"CONFIG", "SET", "slowlog-max-len", "1024"},
+		{"SLOWLOG", "RESET"},
+	} {
+		if _, err := redisCommand(name, args...); err != nil {
+			return fmt.Errorf("failed to enable SLOWLOG: %v", err)
+		}
+	}
+	return nil
+}
+
+func redisProfileOff(name string, state *profileState) error {
+	for _, setting := range []string{"slowlog-log-slower-than", "slowlog-max-len"} {
+		if value, ok := state.Settings[setting]; ok {
+			if _, err := redisCommand(name, "CONFIG", "SET", setting, value); err != nil {
+				return fmt.Errorf("failed to restore %s: %v", setting, err)
+			}
+		}
+	}
+	return nil
+}
+
+// redisProfileTail polls SLOWLOG, Redis has no log stream of its queries
+func redisProfileTail(name string) error {
+	lastID := int64(-1)
+	for {
+		out, err := redisCommand(name, "EVAL", redisSlowlogScript, "0")
+		if err != nil {
+			return fmt.Errorf("failed to read SLOWLOG: %v", err)
+		}
+		lines := strings.Split(out, "\n")
+		// SLOWLOG GET returns the newest entry first
+		for i := len(lines) - 1; i >= 0; i-- {
+			parts := strings.SplitN(lines[i], "\t", 4)
+			if len(parts) != 4 {
+				continue
+			}
+			id, _ := strconv.ParseInt(parts[0], 10, 64)
+			if id <= lastID {
+				continue
+			}
+			lastID = id
+			micros, _ := strconv.ParseFloat(parts[2], 64)
+			printQuery(fmt.Sprintf("%.2f ms", micros/1000), parts[3])
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// ProfileInteractive lets the user pick a container and a profiling action from menus
+func ProfileInteractive() {
+	instance, ok := selectRunningInstance("Select database to profile")
+	if !ok {
+		return
+	}
+
+	actionPrompt := promptui.Select{
+		Label: fmt.Sprintf("Query profiling on %s", instance.Name),
+		Items: []string{"Log slow queries", "Log all queries", "Watch recorded queries", "Turn off and restore settings", "Back"},
+	}
+	_, choice, err := actionPrompt.Run()
+	if err != nil || choice == "Back" {
+		fmt.Println("\n⚠️ Cancelled")
+		return
+	}
+
+	opts := ProfileOptions{ThresholdMs: 100, Since: "10m"}
+	action := "on"
+	switch choice {
+	case "Log slow queries":
+		threshold := tools.AskForInput("Log queries slower than (ms)", "100")
+		if ms, err := strconv.Atoi(strings.TrimSpace(threshold)); err == nil && ms >= 0 {
+			opts.ThresholdMs = ms
+		}
+	case "Log all queries":
+		opts.All = true
+	case "Watch recorded queries":
+		action = "tail"
+	case "Turn off and restore settings":
+		action = "off"
+	}
+
+	if err := ProfileCommand(instance.Name, action, opts); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
@@ -0,0 +1,142 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// ResourceLimits are the docker run limits of a database container. Empty
+// fields leave the Docker default (unlimited, or 64MB of /dev/shm).
+type ResourceLimits struct {
+	Memory  string // e.g. "2g", "512m"
+	CPUs    string // e.g. "2", "0.5"
+	ShmSize string // e.g. "256m"
+}
+
+func (l ResourceLimits) isEmpty() bool {
+	return l.Memory == "" && l.CPUs == "" && l.ShmSize == ""
+}
+
+func (l ResourceLimits) String() string {
+	var parts []string
+	if l.Memory != "" {
+		parts = append(parts, "memory "+l.Memory)
+	}
+	if l.CPUs != "" {
+		parts = append(parts, "cpus "+l.CPUs)
+	}
+	if l.ShmSize != "" {
+		parts = append(parts, "shm "+l.ShmSize)
+	}
+	if len(parts) == 0 {
+		return "no limits"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// resolveResourceLimits returns the limits for a new container: the values
+// given on the command line, or the user's choice between the recommended
+// defaults, custom values and no limits
+func resolveResourceLimits(database string, recommended ResourceLimits, fromFlags ResourceLimits) (ResourceLimits, error) {
+	limits := fromFlags
+	if limits.isEmpty() {
+		items := []string{fmt.Sprintf("Recommended (%s)", recommended), "Custom", "No limits"}
+		if recommended.isEmpty() {
+			items = items[1:]
+		}
+		prompt := promptui.Select{
+			Label: fmt.Sprintf("Resource limits for %s", database),
+			Items: items,
+		}
+		_, choice, err := prompt.Run()
+		if err != nil {
+			return ResourceLimits{}, fmt.Errorf("cancelled")
+		}
+		switch {
+		case strings.HasPrefix(choice, "Recommended"):
+			limits = recommended
+		case choice == "Custom":
+			limits = ResourceLimits{
+				Memory:  strings.TrimSpace(tools.AskForInput("Memory limit (e.g. 2g, 512m; empty for none)", recommended.Memory)),
+				CPUs:    strings.TrimSpace(tools.AskForInput("CPU limit (e.g. 2, 0.5; empty for none)", recommended.CPUs)),
+				ShmSize: strings.TrimSpace(tools.AskForInput("Shared memory size (e.g. 256m; empty for default)", recommended.ShmSize)),
+			}
+		}
+	}
+
+	if limits.Memory != "" {
+		mb, err := parseMemoryMB(limits.Memory)
+		if err != nil {
+			return ResourceLimits{}, err
+		}
+		if mb < 6 {
+			return ResourceLimits{}, fmt.Errorf("memory limit '%s' is too small, Docker requires at least 6m", limits.Memory)
+		}
+		if err := Docker.CheckMemoryLimit(mb); err != nil {
+			return ResourceLimits{}, err
+		}
+	}
+	if limits.ShmSize != "" {
+		if _, err := parseMemoryMB(limits.ShmSize); err != nil {
+			return ResourceLimits{}, err
+		}
+	}
+	if limits.CPUs != "" {
+		if cpus, err := strconv.ParseFloat(limits.CPUs, 64); err != nil || cpus <= 0 {
+			return ResourceLimits{}, fmt.Errorf("invalid CPU limit '%s'", limits.CPUs)
+		}
+	}
+	return limits, nil
+}
+
+// resourceArgs returns the docker run flags for the limits, including the JVM
+// heap of engines that do not size it from the container limit by themselves
+func resourceArgs(database string, limits ResourceLimits) []string {
+	var args []string
+	if limits.Memory != "" {
+		args = append(args, "--memory", limits.Memory)
+	}
+	if limits.CPUs != "" {
+		args = append(args, "--cpus", limits.CPUs)
+	}
+	if limits.ShmSize != "" {
+		args = append(args, "--shm-size", limits.ShmSize)
+	}
+
+	jvmHeapEnv := map[string]string{
+		"elasticsearch": "ES_JAVA_OPTS",
+		"opensearch":    "OPENSEARCH_JAVA_OPTS",
+	}
+	if envName, ok := jvmHeapEnv[database]; ok && limits.Memory != "" {
+		// Half of the container for the heap, the rest for off-heap and the file cache
+		if mb, err := parseMemoryMB(limits.Memory); err == nil {
+			heap := mb / 2
+			args = append(args, "-e", fmt.Sprintf("%s=-Xms%dm -Xmx%dm", envName, heap, heap))
+		}
+	}
+	return args
+}
+
+// parseMemoryMB converts a Docker memory size (b, k, m or g suffix) to megabytes
+func parseMemoryMB(size string) (int64, error) {
+	value := strings.ToLower(strings.TrimSpace(size))
+	multipliers := map[string]float64{"b": 1.0 / (1024 * 1024), "k": 1.0 / 1024, "m": 1, "g": 1024}
+
+	unit := "b"
+	if n := len(value); n > 0 {
+		if _, ok := multipliers[value[n-1:]]; ok {
+			unit = value[n-1:]
+			value = value[:n-1]
+		}
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid memory size '%s' (expected e.g. 512m or 2g)", size)
+	}
+	return int64(number * multipliers[unit]), nil
+}
@@ -0,0 +1,342 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// seedExtensions are the seed files each engine group accepts
+var seedExtensions = map[string][]string{
+	"postgresql":    {".sql", ".sql.gz", ".sh"},
+	"mysql":         {".sql", ".sql.gz", ".sh"},
+	"mongodb":       {".js", ".sh"},
+	"redis":         {".redis", ".txt"},
+	"elasticsearch": {".json", ".ndjson"},
+	"qdrant":        {".json"},
+}
+
+// seedEngine groups engines that load the same kind of seed files
+func seedEngine(engine string) string {
+	switch engine {
+	case "postgresql", "pgvector":
+		return "postgresql"
+	case "mysql", "mariadb":
+		return "mysql"
+	case "mongodb", "qdrant":
+		return engine
+	case "redis", "redis-stack", "valkey", "keydb":
+		return "redis"
+	case "elasticsearch", "opensearch":
+		return "elasticsearch"
+	}
+	return ""
+}
+
+// hasInitHook reports whether the engine's image runs the files of
+// /docker-entrypoint-initdb.d itself when its data directory is empty
+func hasInitHook(engine string) bool {
+	switch seedEngine(engine) {
+	case "postgresql", "mysql", "mongodb":
+		return true
+	}
+	return false
+}
+
+// collectSeedFiles returns the seed files at path: the file itself, or the
+// matching files of a directory in name order, as the images run them
+func collectSeedFiles(engine, path string) ([]string, error) {
+	extensions := seedExtensions[seedEngine(engine)]
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		if !hasSeedExtension(path, extensions) {
+			return nil, fmt.Errorf("%s is not a seed file for %s (expected %s)", path, engine, strings.Join(extensions, ", "))
+		}
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && hasSeedExtension(entry.Name(), extensions) {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s files found in %s", strings.Join(extensions, ", "), path)
+	}
+	return files, nil
+}
+
+func hasSeedExtension(path string, extensions []string) bool {
+	for _, extension := range extensions {
+		if strings.HasSuffix(path, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveInitFiles returns the seed files to load into a new instance, from
+// --init or by asking. Returns nil when there is nothing to load.
+func resolveInitFiles(database, initPath string) ([]string, error) {
+	if seedEngine(database) == "" {
+		if initPath != "" {
+			return nil, fmt.Errorf("--init is not supported for %s", database)
+		}
+		return nil, nil
+	}
+	if initPath == "" {
+		if !Docker.AskYesNo("Do you want to load seed files on first start?") {
+			return nil, nil
+		}
+		fmt.Printf("Accepted files: %s\n", strings.Join(seedExtensions[seedEngine(database)], ", "))
+		path, err := SelectFileOrDirectory("Enter path to a seed file or directory", "./")
+		if err != nil {
+			return nil, err
+		}
+		initPath = path
+	}
+	return collectSeedFiles(database, initPath)
+}
+
+// copyInitFiles copies seed files into a created, not yet started, container's
+// /docker-entrypoint-initdb.d. Copying rather than mounting also works on remote engines.
+func copyInitFiles(containerName string, files []string) error {
+	for _, file := range files {
+		cmd := Docker.Command("cp", file, containerName+":/docker-entrypoint-initdb.d/"+filepath.Base(file))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to copy %s: %v %s", file, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// SeedCommand handles `containdb seed <container> <files...>`, loading seed
+// files into a running instance
+func SeedCommand(name string, paths []string) error {
+	instance, err := Docker.GetContainDBInstance(name)
+	if err != nil {
+		return err
+	}
+	if instance.State != "running" {
+		return fmt.Errorf("%s is %s, start it first with `containdb start %s`", name, instance.State, name)
+	}
+
+	engine := Docker.DetectEngine(name)
+	if seedEngine(engine) == "" {
+		return fmt.Errorf("seeding is supported for PostgreSQL, MySQL, MariaDB, MongoDB, Redis-compatible, Elasticsearch, OpenSearch and Qdrant containers only")
+	}
+
+	var files []string
+	for _, path := range paths {
+		found, err := collectSeedFiles(engine, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, found...)
+	}
+	return seedContainer(name, engine, files)
+}
+
+// seedContainer loads seed files into a running container, stopping at the first failure
+func seedContainer(name, engine string, files []string) error {
+	if seedEngine(engine) == "redis" {
+		if err := waitForRedis(name); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		fmt.Printf("🌱 Loading %s...\n", filepath.Base(file))
+		if err := seedFile(name, engine, file); err != nil {
+			return fmt.Errorf("%s: %v", filepath.Base(file), err)
+		}
+	}
+	fmt.Printf("✅ Loaded %d seed file(s) into %s\n", len(files), name)
+	return nil
+}
+
+func seedFile(name, engine, file string) error {
+	data, err := readSeedFile(file)
+	if err != nil {
+		return err
+	}
+	// Shell scripts run inside the container, with its environment
+	if strings.HasSuffix(file, ".sh") {
+		_, err := Docker.ExecWithInput(name, data, nil, "sh", "-s")
+		return err
+	}
+
+	switch seedEngine(engine) {
+	case "postgresql":
+		_, err = Docker.ExecWithInput(name, data, nil, append(postgresClient(name), "-X", "-q", "-v", "ON_ERROR_STOP=1")...)
+	case "mysql":
+		client, env := mysqlClient(name)
+		command := []string{client, "-uroot"}
+		if database := firstContainerEnv(name, "MARIADB_DATABASE", "MYSQL_DATABASE"); database != "" {
+			command = append(command, database)
+		}
+		_, err = Docker.ExecWithInput(name, data, env, command...)
+	case "mongodb":
+		err = seedMongo(name, data)
+	case "redis":
+		err = seedRedis(name, data)
+	case "elasticsearch":
+		err = seedSearch(name, engine, file, data)
+	case "qdrant":
+		err = seedQdrant(name, file, data)
+	}
+	return err
+}
+
+// readSeedFile reads a seed file, decompressing .gz dumps
+func readSeedFile(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil || !strings.HasSuffix(file, ".gz") {
+		return data, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func firstContainerEnv(name string, keys ...string) string {
+	for _, key := range keys {
+		if value := Docker.GetContainerEnv(name, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// seedMongo runs a script against the database the image's init scripts use
+func seedMongo(name string, script []byte) error {
+	const path = "/tmp/containdb-seed.js"
+	if err := Docker.WriteFileInContainer(name, path, string(script)); err != nil {
+		return err
+	}
+	defer Docker.ExecInContainer(name, nil, "rm", "-f", path)
+
+	database := Docker.GetContainerEnv(name, "MONGO_INITDB_DATABASE")
+	if database == "" {
+		database = "test"
+	}
+	_, err := Docker.ExecInContainer(name, nil, append(mongoShell(name), database, "--file", path)...)
+	return err
+}
+
+// waitForRedis waits until a freshly started Redis-compatible container answers
+func waitForRedis(name string) error {
+	for i := 0; i < 30; i++ {
+		if out, err := redisCommand(name, "PING"); err == nil && out == "PONG" {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("%s did not answer PING", name)
+}
+
+// seedRedis sends a file of commands, one per line, through the CLI
+func seedRedis(name string, commands []byte) error {
+	out, err := Docker.ExecWithInput(name, commands, redisAuthEnv(name), redisCLI(name)...)
+	if err != nil {
+		return err
+	}
+	// The CLI keeps going after a failed command, so look for its error replies
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "ERR ") || strings.HasPrefix(line, "WRONGTYPE ") || strings.HasPrefix(line, "(error)") {
+			return fmt.Errorf("%s", line)
+		}
+	}
+	return nil
+}
+
+// seedSearch installs a .json index template or sends a .ndjson file to the bulk API
+func seedSearch(name, engine, file string, data []byte) error {
+	baseURL, auth := searchEndpoint(name, engine)
+
+	if strings.HasSuffix(file, ".ndjson") {
+		args := append(auth, "-X", "POST", "-H", "Content-Type: application/x-ndjson", "--data-binary", "@-", baseURL+"/_bulk?refresh=true")
+		out, err := Docker.NetworkCurlInput(data, args...)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(out, `"errors":true`) {
+			return fmt.Errorf("some documents were rejected: %s", out)
+		}
+		return nil
+	}
+
+	template := strings.TrimSuffix(filepath.Base(file), ".json")
+	args := append(auth, "-X", "PUT", "-H", "Content-Type: application/json", "--data-binary", "@-", baseURL+"/_index_template/"+template)
+	_, err := Docker.NetworkCurlInput(data, args...)
+	return err
+}
+
+// searchEndpoint returns the REST address of an Elasticsearch or OpenSearch
+// container and the curl arguments authenticating as its superuser
+func searchEndpoint(name, engine string) (string, []string) {
+	if engine == "opensearch" {
+		// The security plugin serves HTTPS with a self-signed certificate
+		return fmt.Sprintf("https://%s:9200", name),
+			[]string{"-k", "-u", "admin:" + Docker.GetContainerEnv(name, "OPENSEARCH_INITIAL_ADMIN_PASSWORD")}
+	}
+	if password := Docker.GetContainerEnv(name, "ELASTIC_PASSWORD"); password != "" {
+		if Docker.GetContainerEnv(name, Docker.TLSCAEnv) != "" {
+			// curl runs in a throwaway container that does not have the local CA
+			return fmt.Sprintf("https://%s:9200", name), []string{"-k", "-u", "elastic:" + password}
+		}
+		return fmt.Sprintf("http://%s:9200", name), []string{"-u", "elastic:" + password}
+	}
+	return fmt.Sprintf("http://%s:9200", name), nil
+}
+
+// seedQdrant creates a collection named after the file from its JSON configuration
+func seedQdrant(name, file string, data []byte) error {
+	collection := strings.TrimSuffix(filepath.Base(file), ".json")
+	args := []string{"-X", "PUT", "-H", "Content-Type: application/json", "--data-binary", "@-",
+		fmt.Sprintf("http://%s:6333/collections/%s", name, collection)}
+	if apiKey := Docker.GetContainerEnv(name, "QDRANT__SERVICE__API_KEY"); apiKey != "" {
+		args = append([]string{"-H", "api-key: " + apiKey}, args...)
+	}
+	_, err := Docker.NetworkCurlInput(data, args...)
+	return err
+}
+
+// SeedInteractive lets the user pick a container and the seed files to load
+func SeedInteractive() {
+	instance, ok := selectRunningInstance("Select database to seed")
+	if !ok {
+		return
+	}
+	engine := Docker.DetectEngine(instance.Name)
+	if seedEngine(engine) == "" {
+		fmt.Println("Error: seeding is not supported for", instance.Name)
+		return
+	}
+
+	fmt.Printf("Accepted files: %s\n", strings.Join(seedExtensions[seedEngine(engine)], ", "))
+	path, err := SelectFileOrDirectory("Enter path to a seed file or directory", "./")
+	if err != nil {
+		fmt.Println("\n⚠️ Cancelled")
+		return
+	}
+	if err := SeedCommand(instance.Name, []string{path}); err != nil {
+		fmt.Println("Error:", err)
+	}
+}
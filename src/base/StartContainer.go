@@ -5,13 +5,25 @@ import (
 	"ContainDB/src/tools"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/manifoldco/promptui"
 )
 
-func StartContainer(database string) {
+// InstallOptions carries values supplied on the command line (`containdb install`)
+// so the matching interactive prompts can be skipped.
+type InstallOptions struct {
+	File      string         // host database file for embedded engines (sqlite, duckdb)
+	Resources ResourceLimits // --memory, --cpus and --shm-size overrides
+	Init      string         // seed file or directory loaded on first start
+	AppUser   *AppUser       // application user created at install; nil asks
+	NoAuth    bool           // start engines that support it without authentication
+	Bind      string         // host address published ports bind to; empty asks
+	TLS       bool           // serve TLS with a certificate from the local CA; false asks
+}
+
+func StartContainer(database string, opts InstallOptions) {
 	imageMap := map[string]string{
 		// Core databases
 		"mongodb":    "mongo",
@@ -20,11 +32,21 @@ func StartContainer(database string) {
 		"postgresql": "postgres",
 		"mariadb":    "mariadb",
 		"axiodb":     "theankansaha/axiodb",
+		// Embedded databases served through a web front-end
+		"sqlite": "coleifer/sqlite-web",
+		"duckdb": "dbeaver/cloudbeaver",
+		// Document / key-value stores
+		"couchdb":   "couchdb",
+		"couchbase": "couchbase",
+		"dynamodb":  "amazon/dynamodb-local",
+		"valkey":    "valkey/valkey",
+		"keydb":     "eqalpha/keydb",
+		"etcd":      "quay.io/coreos/etcd:v3.5.17",
 		// Vector databases
 		"qdrant":        "qdrant/qdrant",
 		"weaviate":      "cr.weaviate.io/semitechnologies/weaviate",
 		"milvus":        "milvusdb/milvus",
-		"chroma":        "chromadb/chroma",
+		"chroma":        "chromadb/chroma:0.6.3", // 1.x dropped the token authentication ContainDB enables
 		"pgvector":      "pgvector/pgvector:pg17",
 		"redis-stack":   "redis/redis-stack",
 		"elasticsearch": "elasticsearch",
@@ -42,6 +64,16 @@ func StartContainer(database string) {
 		"postgresql": "5432",
 		"mariadb":    "3306",
 		"axiodb":     "27018",
+		// Embedded databases served through a web front-end
+		"sqlite": "8080",
+		"duckdb": "8978",
+		// Document / key-value stores
+		"couchdb":   "5984",
+		"couchbase": "8091",
+		"dynamodb":  "8000",
+		"valkey":    "6379",
+		"keydb":     "6379",
+		"etcd":      "2379",
 		// Vector databases
 		"qdrant":        "6333",
 		"weaviate":      "8080",
@@ -56,16 +88,69 @@ func StartContainer(database string) {
 		"typesense":     "8108",
 	}
 
-	// Secondary ports exposed automatically alongside the primary port.
-	secondaryPorts := map[string]string{
-		"qdrant":        "6334",  // gRPC
-		"weaviate":      "50051", // gRPC
-		"milvus":        "9091",  // metrics / management
-		"redis-stack":   "8001",  // built-in RedisInsight UI
-		"elasticsearch": "9300",  // cluster transport
-		"opensearch":    "9600",  // performance analyzer
-		"vespa":         "19071", // config server
-		"axiodb":        "27019", // internal port
+	// Secondary ports, each role published only when the user asks for it
+	secondaryPorts := map[string][]PortRole{
+		"qdrant":        {{"gRPC", []string{"6334"}}},
+		"weaviate":      {{"gRPC", []string{"50051"}}},
+		"milvus":        {{"metrics", []string{"9091"}}},
+		"redis-stack":   {{"RedisInsight UI", []string{"8001"}}},
+		"elasticsearch": {{"cluster transport", []string{"9300"}}},
+		"opensearch":    {{"performance analyzer", []string{"9600"}}},
+		"vespa":         {{"config server", []string{"19071"}}},
+		"axiodb":        {{"internal", []string{"27019"}}},
+		"etcd":          {{"peer", []string{"2380"}}},
+		"couchbase": {
+			{"views, query, search, analytics and eventing", []string{"8092", "8093", "8094", "8095", "8096"}},
+			{"KV data (SDKs)", []string{"11210"}},
+		},
+	}
+
+	// Recommended limits so heavy engines cannot take the whole machine. PostgreSQL
+	// needs more than Docker's 64MB /dev/shm for parallel queries.
+	recommendedResources := map[string]ResourceLimits{
+		// Core databases
+		"mongodb":    {Memory: "1g"},
+		"redis":      {Memory: "512m"},
+		"mysql":      {Memory: "1g"},
+		"postgresql": {Memory: "1g", ShmSize: "256m"},
+		"mariadb":    {Memory: "1g"},
+		"axiodb":     {Memory: "512m"},
+		// Embedded databases served through a web front-end
+		"sqlite": {Memory: "256m"},
+		"duckdb": {Memory: "1g"},
+		// Document / key-value stores
+		"couchdb":   {Memory: "512m"},
+		"couchbase": {Memory: "2g", CPUs: "2"},
+		"dynamodb":  {Memory: "512m"},
+		"valkey":    {Memory: "512m"},
+		"keydb":     {Memory: "512m"},
+		"etcd":      {Memory: "256m"},
+		// Vector databases
+		"qdrant":        {Memory: "1g"},
+		"weaviate":      {Memory: "1g"},
+		"milvus":        {Memory: "4g", CPUs: "2"},
+		"chroma":        {Memory: "1g"},
+		"pgvector":      {Memory: "1g", ShmSize: "256m"},
+		"redis-stack":   {Memory: "512m"},
+		"elasticsearch": {Memory: "2g", CPUs: "2"},
+		"opensearch":    {Memory: "2g", CPUs: "2"},
+		"marqo":         {Memory: "4g", CPUs: "2"},
+		"vespa":         {Memory: "4g", CPUs: "2"},
+		"typesense":     {Memory: "512m"},
+	}
+
+	// Host requirements checked before the install. DataGB is the initial data
+	// on top of the image, whose size is read from the registry; engines not
+	// listed need 2GB of RAM and 1GB of data.
+	requirements := map[string]EngineRequirements{
+		"elasticsearch": {RAMGB: 4, DataGB: 2, MaxMapCount: true},
+		"opensearch":    {RAMGB: 4, DataGB: 2, MaxMapCount: true},
+		"milvus":        {RAMGB: 8, DataGB: 2},
+		"vespa":         {RAMGB: 8, DataGB: 2},
+		"marqo":         {RAMGB: 8, DataGB: 4}, // downloads its embedding models on first start
+		"couchbase":     {RAMGB: 4, DataGB: 2},
+		"mysql":         {RAMGB: 2, DataGB: 1},
+		"mongodb":       {RAMGB: 2, DataGB: 1},
 	}
 
 	image := imageMap[database]
@@ -76,47 +161,108 @@ func StartContainer(database string) {
 		return
 	}
 
+	// The database file would have to exist on the remote machine, not this one
+	if isEmbeddedEngine(database) && Docker.IsRemoteEngine() {
+		fmt.Printf("❌ %s mounts a database file from this machine and cannot run on the remote engine at %s.\n", database, Docker.EngineHost())
+		return
+	}
+
+	req, ok := requirements[database]
+	if !ok {
+		req = EngineRequirements{RAMGB: 2, DataGB: 1}
+	}
+	if !runPreflight(database, image, port, rolePorts(secondaryPorts[database]), req) {
+		fmt.Println("Exiting setup.")
+		return
+	}
+
 	// Pull image
 	fmt.Printf("Pulling image %s...\n", image)
-	cmd := exec.Command("docker", "pull", image)
+	cmd := Docker.Command("pull", image)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	_ = cmd.Run()
 
 	// Ask for port mapping
-	portMapping := ""
+	var portArgs []string
+	publishedPort := ""
+	bindAddress := ""
+	var err error
 	if Docker.AskYesNo("Do you want to map container port with host?") {
-		customPort := Docker.AskYesNo("Do you want to use custom host port?")
-		if customPort {
-			hostPort := tools.AskForInput("Enter custom host port", port)
-			// For axiodb, reject 27019 as it's needed internally
-			if database == "axiodb" && hostPort == "27019" {
-				fmt.Println("Port 27019 is reserved for internal use. Please choose a different port.")
+		if bindAddress, err = tools.SelectBindAddress(opts.Bind); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		hostPort := port
+		if Docker.AskYesNo("Do you want to use custom host port?") {
+			hostPort = tools.AskForInput("Enter custom host port", port)
+			for {
+				// For axiodb, reject 27019 as it's needed internally
+				if database == "axiodb" && hostPort == "27019" {
+					fmt.Println("Port 27019 is reserved for internal use. Please choose a different port.")
+				} else if check := Docker.CheckPort(hostPort); check.Status == Docker.CheckFail {
+					fmt.Printf("❌ Port %s %s. %s\n", hostPort, check.Message, check.Remediation)
+				} else {
+					if check.Status == Docker.CheckWarn {
+						fmt.Printf("⚠️  Port %s %s: %s\n", hostPort, check.Message, check.Remediation)
+					}
+					break
+				}
 				hostPort = tools.AskForInput("Enter custom host port", port)
 			}
-			portMapping = fmt.Sprintf("-p %s:%s", hostPort, port)
-		} else {
-			portMapping = fmt.Sprintf("-p %s:%s", port, port)
 		}
+		portArgs = publishArgs(bindAddress, hostPort, port)
+		publishedPort = hostPort
 	}
 
-	// Automatically add secondary ports for databases that need them
-	if secPort, ok := secondaryPorts[database]; ok {
-		if portMapping != "" {
-			portMapping += fmt.Sprintf(" -p %s:%s", secPort, secPort)
-		} else {
-			portMapping = fmt.Sprintf("-p %s:%s", secPort, secPort)
+	// Secondary ports are published per role, on the same interface as the primary port
+	for _, role := range secondaryPorts[database] {
+		noun := "port"
+		if len(role.Ports) > 1 {
+			noun = "ports"
+		}
+		if !Docker.AskYesNo(fmt.Sprintf("Publish the %s %s %s?", role.Role, noun, strings.Join(role.Ports, ", "))) {
+			continue
+		}
+		if bindAddress == "" {
+			if bindAddress, err = tools.SelectBindAddress(opts.Bind); err != nil {
+				fmt.Println("Error:", err)
+				return
+			}
+		}
+		for _, secPort := range role.Ports {
+			portArgs = append(portArgs, publishArgs(bindAddress, secPort, secPort)...)
 		}
 	}
 
 	restartFlag := ""
 	if Docker.AskYesNo("Do you want the container to auto-restart on system startup?") {
 		restartFlag = "--restart unless-stopped"
+		if Docker.RuntimeName() == "podman" {
+			fmt.Println("ℹ️  Podman restarts containers after a reboot only with podman-restart.service enabled:")
+			fmt.Println("   systemctl --user enable podman-restart.service   (rootless)")
+			fmt.Println("   sudo systemctl enable podman-restart.service     (rootful)")
+		}
+	}
+
+	resources, err := resolveResourceLimits(database, recommendedResources[database], opts.Resources)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
 	}
 
-	// Ask for data persistence
+	// Embedded engines browse a database file from the host instead of a volume
 	volumeMapping := ""
-	if Docker.AskYesNo("Do you want to persist data?") {
+	reusedVolume := false
+	var embeddedArgs []string
+	if isEmbeddedEngine(database) {
+		dbFile, err := resolveEmbeddedFile(database, opts.File)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		embeddedArgs = embeddedMountArgs(database, dbFile)
+	} else if Docker.AskYesNo("Do you want to persist data?") {
 		containerDirs := map[string]string{
 			// Core databases
 			"mongodb":    "/data/db",
@@ -125,6 +271,13 @@ func StartContainer(database string) {
 			"postgresql": "/var/lib/postgresql/data",
 			"mariadb":    "/var/lib/mysql",
 			"axiodb":     "/app/AxioDB",
+			// Document / key-value stores
+			"couchdb":   "/opt/couchdb/data",
+			"couchbase": "/opt/couchbase/var",
+			"dynamodb":  "/home/dynamodblocal/data",
+			"valkey":    "/data",
+			"keydb":     "/data",
+			"etcd":      "/etcd-data",
 			// Vector databases
 			"qdrant":        "/qdrant/storage",
 			"weaviate":      "/var/lib/weaviate",
@@ -156,6 +309,7 @@ func StartContainer(database string) {
 					fmt.Println("Exiting setup.")
 					return
 				}
+				reusedVolume = choice == "Use existing"
 			} else {
 				_ = Docker.CreateVolume(volName)
 			}
@@ -165,8 +319,26 @@ func StartContainer(database string) {
 		}
 	}
 
+	// Engines that used to start open get a password or API key unless opted out
+	secret, err := resolveAuthSecret(database, opts.NoAuth)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
 	env := ""
+	// Credentials needed by the post-start setup of engines configured over their REST API
+	adminUser, adminPass := "", ""
 	switch database {
+	case "mongodb":
+		if secret != "" {
+			user := tools.AskForInput("Enter root username", "admin")
+			if user == "" {
+				user = "admin"
+			}
+			env = fmt.Sprintf("-e MONGO_INITDB_ROOT_USERNAME=%s -e MONGO_INITDB_ROOT_PASSWORD=%s", user, secret)
+		}
+
 	case "mysql":
 		fmt.Println("You need to set environment variables for MySQL.")
 		pass := tools.AskForInput("Enter root password", "")
@@ -236,7 +408,12 @@ func StartContainer(database string) {
 		env = fmt.Sprintf("-e discovery.type=single-node -e OPENSEARCH_INITIAL_ADMIN_PASSWORD=%s", pass)
 
 	case "weaviate":
-		env = "-e AUTHENTICATION_ANONYMOUS_ACCESS_ENABLED=true -e PERSISTENCE_DATA_PATH=/var/lib/weaviate"
+		env = fmt.Sprintf("-e AUTHENTICATION_ANONYMOUS_ACCESS_ENABLED=%t -e PERSISTENCE_DATA_PATH=/var/lib/weaviate", secret == "")
+
+	case "milvus":
+		if secret != "" {
+			adminUser, adminPass = "root", secret
+		}
 
 	case "typesense":
 		fmt.Println("Typesense requires an API key for all requests.")
@@ -246,17 +423,121 @@ func StartContainer(database string) {
 			os.Exit(1)
 		}
 		env = fmt.Sprintf("-e TYPESENSE_DATA_DIR=/data -e TYPESENSE_API_KEY=%s", apiKey)
+
+	case "couchdb":
+		fmt.Println("You need to set an admin user for CouchDB.")
+		adminUser = tools.AskForInput("Enter admin username", "admin")
+		adminPass = tools.AskForInput("Enter admin password", "")
+		if adminUser == "" {
+			adminUser = "admin"
+		}
+		if adminPass == "" {
+			fmt.Println("Error: Password cannot be empty.")
+			os.Exit(1)
+		}
+		env = fmt.Sprintf("-e COUCHDB_USER=%s -e COUCHDB_PASSWORD=%s", adminUser, adminPass)
+
+	case "couchbase":
+		fmt.Println("The Couchbase cluster will be initialized with an administrator account.")
+		fmt.Println("Password must be at least 6 characters.")
+		adminUser = tools.AskForInput("Enter administrator username", "Administrator")
+		adminPass = tools.AskForInput("Enter administrator password", "")
+		if adminUser == "" {
+			adminUser = "Administrator"
+		}
+		if len(adminPass) < 6 {
+			fmt.Println("Error: Password must be at least 6 characters.")
+			os.Exit(1)
+		}
+	}
+
+	appUser := opts.AppUser
+	if appUser == nil {
+		if appUser, err = askAppUser(database); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+	if appUser != nil && database == "elasticsearch" && !strings.Contains(env, "ELASTIC_PASSWORD=") {
+		fmt.Println("⚠️  Security is disabled, so no application user will be created.")
+		appUser = nil
+	}
+
+	seedFiles, err := resolveInitFiles(database, opts.Init)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	useTLS, err := resolveTLS(database, opts.TLS, env)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
 	}
 
 	containerName := fmt.Sprintf("%s-container", database)
 
+	// Application users of images with an init hook are created by an init
+	// script, which runs before the seed files
+	appEnv, appCommand, appScript := appUserSetup(database, appUser)
+	userCreatedAtStart := appEnv != nil || appCommand != nil || appScript != ""
+	if secret != "" && database != "mongodb" {
+		appEnv = append(appEnv, authEnv(database, secret)...)
+	}
+	// The certificate files are copied into the created container before it starts
+	var tlsFiles []Docker.ContainerFile
+	if useTLS {
+		files, tlsEnv, tlsOptions, err := tlsSetup(database, image, containerName)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		tlsFiles = files
+		appEnv = append(appEnv, tlsEnv...)
+		appCommand = append(appCommand, tlsOptions...)
+	}
+	// Redis-compatible servers take the password, the application user and TLS as options
+	if userEngine(database) == "redis" {
+		options := appCommand
+		if secret != "" {
+			options = append(redisAuthArgs(secret), options...)
+		}
+		serverEnv, serverCommand := redisServerCommand(database, options)
+		appEnv, appCommand = append(appEnv, serverEnv...), serverCommand
+	}
+	if appScript != "" {
+		scriptDir, err := os.MkdirTemp("", "containdb-init")
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		defer os.RemoveAll(scriptDir)
+		scriptPath := filepath.Join(scriptDir, appScript)
+		if err := os.WriteFile(scriptPath, []byte(appUserScript(database, *appUser)), 0600); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		seedFiles = append([]string{scriptPath}, seedFiles...)
+	}
+	// Images with an init hook run the files themselves; the container is
+	// created first so they can be copied in before it starts
+	initHook := len(seedFiles) > 0 && hasInitHook(database)
+	milvusAuth := database == "milvus" && secret != ""
+	if initHook && reusedVolume {
+		fmt.Println("⚠️  The existing volume already holds data, so the image will skip the init scripts.")
+		fmt.Printf("   Load them afterwards with: containdb seed %s-container <files>\n", database)
+		if appScript != "" {
+			fmt.Printf("   Create the application user with: containdb user add %s-container %s\n", database, appUser.Name)
+		}
+	}
+
 	// Build docker run command as args array
 	args := []string{"run", "-d", "--network", "ContainDB-Network"}
-
-	if portMapping != "" {
-		args = append(args, strings.Fields(portMapping)...)
+	if initHook || milvusAuth || useTLS {
+		args = []string{"create", "--network", "ContainDB-Network"}
 	}
 
+	args = append(args, portArgs...)
+
 	if restartFlag != "" {
 		args = append(args, strings.Fields(restartFlag)...)
 	}
@@ -268,25 +549,87 @@ func StartContainer(database string) {
 	if env != "" {
 		args = append(args, strings.Fields(env)...)
 	}
+	for _, e := range appEnv {
+		args = append(args, "-e", e)
+	}
+
+	args = append(args, embeddedArgs...)
+	args = append(args, resourceArgs(database, resources)...)
+
+	// DynamoDB Local runs as an unprivileged user that cannot write to a fresh named volume
+	if database == "dynamodb" && volumeMapping != "" {
+		args = append(args, "--user", "root")
+	}
 
 	args = append(args, "--name", containerName, image)
 
 	// Some databases need a startup command appended after the image name
 	containerCommands := map[string]string{
-		"milvus": "milvus run standalone",
+		"milvus":   "milvus run standalone",
+		"dynamodb": "-jar DynamoDBLocal.jar -sharedDb -inMemory",
+		"etcd": "etcd --name etcd0 --data-dir /etcd-data" +
+			" --listen-client-urls http://0.0.0.0:2379" +
+			" --advertise-client-urls http://" + containerName + ":2379" +
+			" --listen-peer-urls http://0.0.0.0:2380",
+	}
+	if database == "dynamodb" && volumeMapping != "" {
+		containerCommands["dynamodb"] = "-jar DynamoDBLocal.jar -sharedDb -dbPath /home/dynamodblocal/data"
 	}
 	if cmdStr, ok := containerCommands[database]; ok {
 		args = append(args, strings.Fields(cmdStr)...)
 	}
+	args = append(args, appCommand...)
 
-	fmt.Println("Running: docker", strings.Join(args, " "))
-	cmd = exec.Command("docker", args...)
+	fmt.Println("Running:", Docker.RuntimeName(), strings.Join(args, " "))
+	cmd = Docker.Command(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	if err == nil && initHook {
+		if err = copyInitFiles(containerName, seedFiles); err == nil {
+			fmt.Printf("🌱 %d init script(s) will run on the first start\n", len(seedFiles))
+		}
+	}
+	if err == nil && milvusAuth {
+		err = copyContentToContainer(containerName, "/milvus/configs/user.yaml", milvusAuthConfig)
+	}
+	if err == nil && useTLS {
+		err = Docker.CopyFilesToContainer(containerName, tlsFiles)
+	}
+	if err == nil && (initHook || milvusAuth || useTLS) {
+		err = Docker.Command("start", containerName).Run()
+	}
+	if err != nil {
 		fmt.Println("Error starting container:", err)
 	} else {
 		fmt.Println("Container started successfully.")
+		if publishedPort != "" {
+			fmt.Printf("🔌 Connect at %s:%s\n", tools.ConnectHost(bindAddress), publishedPort)
+		}
+		if useTLS {
+			host, hostPort := instanceAddress(containerName, tlsPorts[database])
+			fmt.Printf("🔒 TLS connection: %s\n", connectionString(containerName, database, host, hostPort))
+		}
+		if err := postStartSetup(database, containerName, adminUser, adminPass); err != nil {
+			fmt.Printf("⚠️  Post-start setup for %s failed: %v\n", database, err)
+		}
+		if appUser != nil {
+			// Engines without an install-time option get the user over their API
+			var userErr error
+			if !userCreatedAtStart {
+				userErr = addUser(containerName, database, *appUser)
+			}
+			if userErr != nil {
+				fmt.Printf("⚠️  Creating the application user failed: %v\n", userErr)
+			} else {
+				printAppUser(database, *appUser)
+			}
+		}
+		if len(seedFiles) > 0 && !initHook {
+			if err := seedContainer(containerName, database, seedFiles); err != nil {
+				fmt.Printf("⚠️  Seeding %s failed: %v\n", containerName, err)
+			}
+		}
 		tools.AfterContainerToolInstaller(database)
 	}
 }
@@ -0,0 +1,280 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tlsDirs is where each engine's certificate files go inside the container.
+// Elasticsearch only reads them from its config directory.
+var tlsDirs = map[string]string{
+	"postgresql":    "/etc/containdb/tls",
+	"pgvector":      "/etc/containdb/tls",
+	"mysql":         "/etc/containdb/tls",
+	"mariadb":       "/etc/containdb/tls",
+	"mongodb":       "/etc/containdb/tls",
+	"redis":         "/etc/containdb/tls",
+	"redis-stack":   "/etc/containdb/tls",
+	"valkey":        "/etc/containdb/tls",
+	"keydb":         "/etc/containdb/tls",
+	"elasticsearch": "/usr/share/elasticsearch/config/certs",
+}
+
+// tlsPorts are the container ports serving TLS, used for connection strings
+var tlsPorts = map[string]string{
+	"postgresql":    "5432",
+	"pgvector":      "5432",
+	"mysql":         "3306",
+	"mariadb":       "3306",
+	"mongodb":       "27017",
+	"redis":         "6379",
+	"redis-stack":   "6379",
+	"valkey":        "6379",
+	"keydb":         "6379",
+	"elasticsearch": "9200",
+}
+
+// tlsServerUsers are the accounts the entrypoints of engine images start their
+// server as, when the image itself runs as root
+var tlsServerUsers = map[string]string{
+	"postgresql": "postgres",
+	"pgvector":   "postgres",
+	"mysql":      "mysql",
+	"mariadb":    "mysql",
+	"mongodb":    "mongodb",
+	"redis":      "redis",
+	"valkey":     "valkey",
+	"keydb":      "keydb",
+}
+
+// tlsOwner returns the uid and gid the server of an image runs as; it must
+// own the private key, PostgreSQL refuses a key others can read. The ids
+// differ between image variants (e.g. 999 on Debian, 70 for Postgres on
+// Alpine), so they are read from the image.
+func tlsOwner(database, image string) (int, int, error) {
+	if Docker.RuntimeName() == "podman" {
+		image = Docker.QualifyImage(image)
+	}
+	out, err := Docker.Command("image", "inspect", "--format", "{{.Config.User}}", image).Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to inspect %s: %v", image, err)
+	}
+	user, group, _ := strings.Cut(strings.TrimSpace(string(out)), ":")
+	if uid, err := strconv.Atoi(user); err == nil {
+		gid, err := strconv.Atoi(group)
+		if err != nil {
+			gid = uid
+		}
+		return uid, gid, nil
+	}
+	if user == "" || user == "root" {
+		if user = tlsServerUsers[database]; user == "" {
+			// The server keeps running as root
+			return 0, 0, nil
+		}
+	}
+
+	out, err = Docker.Command("run", "--rm", "--entrypoint", "sh", image, "-c", `id -u "$1" && id -g "$1"`, "sh", user).Output()
+	ids := strings.Fields(string(out))
+	if err != nil || len(ids) != 2 {
+		if _, ok := tlsServerUsers[database]; ok && err != nil {
+			// No such account in this variant, whose server then runs as root
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("failed to read the ids of user %s in %s: %v", user, image, err)
+	}
+	uid, uidErr := strconv.Atoi(ids[0])
+	gid, gidErr := strconv.Atoi(ids[1])
+	if uidErr != nil || gidErr != nil {
+		return 0, 0, fmt.Errorf("unexpected ids %q of user %s in %s", ids, user, image)
+	}
+	return uid, gid, nil
+}
+
+// resolveTLS reports whether a new instance serves TLS, from --tls or by asking
+func resolveTLS(database string, enable bool, env string) (bool, error) {
+	if _, ok := tlsDirs[database]; !ok {
+		if enable {
+			return false, fmt.Errorf("--tls is not supported for %s", database)
+		}
+		return false, nil
+	}
+	if !enable && !Docker.AskYesNo("Serve TLS with a certificate from the ContainDB local CA?") {
+		return false, nil
+	}
+	// Elasticsearch serves HTTPS only with its security features on
+	if database == "elasticsearch" && !strings.Contains(env, "ELASTIC_PASSWORD=") {
+		return false, fmt.Errorf("TLS for Elasticsearch needs security enabled")
+	}
+	return true, nil
+}
+
+// tlsSetup issues a server certificate for a container and returns the files
+// to copy into it, with the environment and server options serving TLS
+func tlsSetup(database, image, containerName string) (files []Docker.ContainerFile, env []string, options []string, err error) {
+	ca, err := Docker.LoadLocalCA()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Reachable as localhost, by name on ContainDB-Network and at the host's addresses
+	hosts := []string{"localhost", containerName, "127.0.0.1", "::1"}
+	if Docker.IsRemoteEngine() {
+		hosts = append(hosts, Docker.EngineHost())
+	} else {
+		for _, iface := range Docker.LocalInterfaces() {
+			hosts = append(hosts, iface.IP)
+		}
+	}
+	certPEM, keyPEM, err := ca.IssueServerCert(containerName, hosts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dir := tlsDirs[database]
+	uid, gid, err := tlsOwner(database, image)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	files = []Docker.ContainerFile{
+		{Path: dir + "/ca.pem", Content: ca.CertPEM(), Mode: 0644, UID: uid, GID: gid},
+		{Path: dir + "/server.pem", Content: certPEM, Mode: 0644, UID: uid, GID: gid},
+		{Path: dir + "/server-key.pem", Content: keyPEM, Mode: 0600, UID: uid, GID: gid},
+	}
+	env = []string{Docker.TLSCAEnv + "=" + dir + "/ca.pem"}
+
+	switch database {
+	case "postgresql", "pgvector":
+		options = []string{"-c", "ssl=on", "-c", "ssl_cert_file=" + dir + "/server.pem", "-c", "ssl_key_file=" + dir + "/server-key.pem"}
+	case "mysql", "mariadb":
+		options = []string{"--ssl-ca=" + dir + "/ca.pem", "--ssl-cert=" + dir + "/server.pem",
+			"--ssl-key=" + dir + "/server-key.pem", "--require-secure-transport=ON"}
+	case "mongodb":
+		// mongod wants the certificate and key in one file
+		files = append(files, Docker.ContainerFile{Path: dir + "/server-combined.pem",
+			Content: append(append([]byte{}, certPEM...), keyPEM...), Mode: 0600, UID: uid, GID: gid})
+		options = []string{"--tlsMode", "requireTLS", "--tlsCertificateKeyFile", dir + "/server-combined.pem",
+			"--tlsCAFile", dir + "/ca.pem", "--tlsAllowConnectionsWithoutCertificates"}
+	case "redis", "redis-stack", "valkey", "keydb":
+		// The plain port is closed, the usual port serves TLS instead
+		options = []string{"--port", "0", "--tls-port", "6379", "--tls-cert-file", dir + "/server.pem",
+			"--tls-key-file", dir + "/server-key.pem", "--tls-ca-cert-file", dir + "/ca.pem", "--tls-auth-clients", "no"}
+	case "elasticsearch":
+		// Paths are relative to the config directory
+		env = append(env,
+			"xpack.security.http.ssl.enabled=true",
+			"xpack.security.http.ssl.certificate=certs/server.pem",
+			"xpack.security.http.ssl.key=certs/server-key.pem",
+			"xpack.security.http.ssl.certificate_authorities=certs/ca.pem")
+	}
+	return files, env, options, nil
+}
+
+// connectionString returns the URL of a TLS instance at host:port, verifying
+// the server with the local CA. Passwords are left out.
+func connectionString(name, database, host, port string) string {
+	caPath := Docker.CACertPath()
+	address := host + ":" + port
+	switch database {
+	case "postgresql", "pgvector":
+		user := Docker.GetContainerEnv(name, "POSTGRES_USER")
+		if user == "" {
+			user = "postgres"
+		}
+		db := Docker.GetContainerEnv(name, "POSTGRES_DB")
+		if db == "" {
+			db = user
+		}
+		return fmt.Sprintf("postgresql://%s@%s/%s?sslmode=verify-full&sslrootcert=%s", user, address, db, url.QueryEscape(caPath))
+	case "mysql", "mariadb":
+		return fmt.Sprintf("mysql://root@%s/?ssl-mode=VERIFY_IDENTITY&ssl-ca=%s", address, url.QueryEscape(caPath))
+	case "mongodb":
+		credentials := ""
+		if user := Docker.GetContainerEnv(name, "MONGO_INITDB_ROOT_USERNAME"); user != "" {
+			credentials = user + "@"
+		}
+		return fmt.Sprintf("mongodb://%s%s/?authSource=admin&tls=true&tlsCAFile=%s", credentials, address, url.QueryEscape(caPath))
+	case "redis", "redis-stack", "valkey", "keydb":
+		// Redis URLs have no CA parameter; clients take it separately
+		return fmt.Sprintf("rediss://default@%s  (CA: %s)", address, caPath)
+	case "elasticsearch":
+		return fmt.Sprintf("https://elastic@%s  (CA: %s)", address, caPath)
+	}
+	return ""
+}
+
+// instanceAddress returns where clients on this machine reach a container
+// port: the published host port, or the container on ContainDB-Network
+func instanceAddress(name, port string) (string, string) {
+	out, err := Docker.Command("port", name, port).Output()
+	if err == nil {
+		// e.g. "127.0.0.1:5432", one line per address family
+		line := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+		if index := strings.LastIndex(line, ":"); index > 0 {
+			host := strings.Trim(line[:index], "[]")
+			return tools.ConnectHost(host), line[index+1:]
+		}
+	}
+	return name, port
+}
+
+// tlsUsage lists the forms of `containdb tls`
+const tlsUsage = "Usage: containdb tls ca | export <path> | url <container>"
+
+// TLSCommand handles `containdb tls ca|export|url`
+func TLSCommand(args []string) error {
+	switch args[0] {
+	case "ca":
+		ca, err := Docker.LoadLocalCA()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🔐 Local CA: %s\n", Docker.CACertPath())
+		fmt.Printf("   Subject: %s\n", ca.Cert.Subject.CommonName)
+		fmt.Printf("   Expires: %s\n", ca.Cert.NotAfter.Format("2006-01-02"))
+		fmt.Printf("   SHA-256: %s\n", ca.Fingerprint())
+		return nil
+
+	case "export":
+		if len(args) != 2 {
+			return fmt.Errorf("%s", tlsUsage)
+		}
+		ca, err := Docker.LoadLocalCA()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(args[1], ca.CertPEM(), 0644); err != nil {
+			return err
+		}
+		path, _ := filepath.Abs(args[1])
+		fmt.Printf("✅ Exported the local CA certificate to %s\n", path)
+		fmt.Println("   Trust it system-wide or in a trust store, e.g.:")
+		fmt.Printf("   Debian/Ubuntu: sudo cp %s /usr/local/share/ca-certificates/containdb.crt && sudo update-ca-certificates\n", path)
+		fmt.Printf("   macOS:         sudo security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain %s\n", path)
+		fmt.Printf("   Java:          keytool -importcert -noprompt -alias containdb -file %s -keystore truststore.jks\n", path)
+		fmt.Printf("   Node.js:       NODE_EXTRA_CA_CERTS=%s\n", path)
+		return nil
+
+	case "url":
+		if len(args) != 2 {
+			return fmt.Errorf("%s", tlsUsage)
+		}
+		name := args[1]
+		if _, err := Docker.GetContainDBInstance(name); err != nil {
+			return err
+		}
+		if Docker.GetContainerEnv(name, Docker.TLSCAEnv) == "" {
+			return fmt.Errorf("%s does not serve TLS; install it with --tls", name)
+		}
+		database := Docker.DetectEngine(name)
+		host, port := instanceAddress(name, tlsPorts[database])
+		fmt.Println(connectionString(name, database, host, port))
+		return nil
+	}
+	return fmt.Errorf("unknown tls command %q\n%s", args[0], tlsUsage)
+}
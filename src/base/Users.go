@@ -0,0 +1,478 @@
+package base
+
+import (
+	"ContainDB/src/Docker"
+	"ContainDB/src/tools"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AppUser is a least-privilege account for an application, scoped to one
+// database (an index pattern on Elasticsearch and OpenSearch, a key prefix on Redis)
+type AppUser struct {
+	Name     string
+	Password string
+	Database string
+	ReadOnly bool
+}
+
+// userEngine groups engines that manage users the same way
+func userEngine(engine string) string {
+	switch engine {
+	case "postgresql", "pgvector":
+		return "postgresql"
+	case "mysql", "mariadb":
+		return "mysql"
+	case "mongodb", "elasticsearch", "opensearch":
+		return engine
+	case "redis", "redis-stack", "valkey", "keydb":
+		return "redis"
+	}
+	return ""
+}
+
+// defaultUserDatabase is the scope a user gets when none is given
+func defaultUserDatabase(engine, user string) string {
+	switch userEngine(engine) {
+	case "redis":
+		return "" // every key
+	case "elasticsearch", "opensearch":
+		return user + "-*"
+	}
+	return user
+}
+
+// UserCommand handles `containdb user add|list|remove <container> [username]`
+func UserCommand(action, name string, args []string, user AppUser) error {
+	instance, err := Docker.GetContainDBInstance(name)
+	if err != nil {
+		return err
+	}
+	if instance.State != "running" {
+		return fmt.Errorf("%s is %s, start it first with `containdb start %s`", name, instance.State, name)
+	}
+	engine := Docker.DetectEngine(name)
+	if userEngine(engine) == "" {
+		return fmt.Errorf("user management is supported for PostgreSQL, MySQL, MariaDB, MongoDB, Redis-compatible, Elasticsearch and OpenSearch containers only")
+	}
+
+	switch action {
+	case "list":
+		users, err := listUsers(name, engine)
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			fmt.Println("No users found.")
+			return nil
+		}
+		fmt.Printf("Users of %s:\n", name)
+		for _, line := range users {
+			fmt.Println(" -", line)
+		}
+		return nil
+	case "add", "remove":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: containdb user %s <container> <username>", action)
+		}
+		user.Name = args[0]
+	default:
+		return fmt.Errorf("unknown action '%s' (expected add, list or remove)", action)
+	}
+
+	if action == "remove" {
+		if err := removeUser(name, engine, user); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Removed user %s from %s\n", user.Name, name)
+		return nil
+	}
+
+	if user.Password == "" {
+		user.Password = tools.AskForInput(fmt.Sprintf("Enter password for %s", user.Name), "")
+		if user.Password == "" {
+			return fmt.Errorf("password cannot be empty")
+		}
+	}
+	if user.Database == "" {
+		user.Database = defaultUserDatabase(engine, user.Name)
+	}
+	if err := addUser(name, engine, user); err != nil {
+		return err
+	}
+	printAppUser(engine, user)
+	return nil
+}
+
+func printAppUser(engine string, user AppUser) {
+	access := "read-write"
+	if user.ReadOnly {
+		access = "read-only"
+	}
+	scope := user.Database
+	switch {
+	case userEngine(engine) == "redis" && scope == "":
+		scope = "all keys"
+	case userEngine(engine) == "redis":
+		scope = "keys " + scope + ":*"
+	}
+	fmt.Printf("👤 User %s has %s access to %s\n", user.Name, access, scope)
+}
+
+// askAppUser asks whether to create an application user at install
+func askAppUser(database string) (*AppUser, error) {
+	if userEngine(database) == "" || !Docker.AskYesNo("Do you want to create an application database and user?") {
+		return nil, nil
+	}
+	label := "Enter database name"
+	switch userEngine(database) {
+	case "redis":
+		label = "Enter key prefix the user may access (empty for all keys)"
+	case "elasticsearch", "opensearch":
+		label = "Enter index pattern the user may access"
+	}
+
+	user := AppUser{Name: tools.AskForInput("Enter application username", "app")}
+	if user.Name == "" {
+		user.Name = "app"
+	}
+	user.Database = tools.AskForInput(label, defaultUserDatabase(database, user.Name))
+	user.Password = tools.AskForInput(fmt.Sprintf("Enter password for %s", user.Name), "")
+	if user.Password == "" {
+		return nil, fmt.Errorf("password cannot be empty")
+	}
+	return &user, nil
+}
+
+// appUserSetup returns how an application user is created at install: the
+// environment, server options or the name of an init script (see appUserScript).
+// Server options of Redis-compatible images go through redisServerCommand.
+func appUserSetup(database string, user *AppUser) (env []string, command []string, script string) {
+	if user == nil {
+		return nil, nil, ""
+	}
+	switch database {
+	case "mysql":
+		return []string{"MYSQL_DATABASE=" + user.Database, "MYSQL_USER=" + user.Name, "MYSQL_PASSWORD=" + user.Password}, nil, ""
+	case "mariadb":
+		return []string{"MARIADB_DATABASE=" + user.Database, "MARIADB_USER=" + user.Name, "MARIADB_PASSWORD=" + user.Password}, nil, ""
+	case "postgresql", "pgvector":
+		return []string{"POSTGRES_DB=" + user.Database}, nil, "00-containdb-users.sql"
+	case "mongodb":
+		return []string{"MONGO_INITDB_DATABASE=" + user.Database}, nil, "00-containdb-users.js"
+	case "redis", "redis-stack", "valkey", "keydb":
+		return nil, redisUserArgs(*user), ""
+	}
+	return nil, nil, ""
+}
+
+// appUserScript returns the init script creating an application user
+func appUserScript(database string, user AppUser) string {
+	if database == "mongodb" {
+		return mongoUserJS(user) + ";\n"
+	}
+	return postgresUserSQL(user)
+}
+
+func addUser(name, engine string, user AppUser) error {
+	switch userEngine(engine) {
+	case "postgresql":
+		_, err := Docker.ExecWithInput(name, []byte(postgresUserSQL(user)), nil, append(postgresClient(name), "-X", "-q", "-v", "ON_ERROR_STOP=1")...)
+		return err
+	case "mysql":
+		_, err := mysqlQuery(name, mysqlUserSQL(user))
+		return err
+	case "mongodb":
+		_, err := mongoEval(name, mongoUserJS(user))
+		return err
+	case "redis":
+		args := append([]string{"ACL", "SETUSER", user.Name}, redisACLRules(user, ">"+user.Password)...)
+		if _, err := redisCommand(name, args...); err != nil {
+			return err
+		}
+		persistRedisACL(name)
+		return nil
+	case "elasticsearch":
+		return elasticsearchAddUser(name, user)
+	case "opensearch":
+		return opensearchAddUser(name, user)
+	}
+	return nil
+}
+
+func listUsers(name, engine string) ([]string, error) {
+	var out string
+	var err error
+	switch userEngine(engine) {
+	case "postgresql":
+		out, err = postgresQuery(name, "SELECT rolname || CASE WHEN rolsuper THEN ' (superuser)' ELSE '' END FROM pg_roles WHERE rolcanlogin ORDER BY rolname")
+	case "mysql":
+		out, err = mysqlQuery(name, "SELECT CONCAT(user, '@', host) FROM mysql.user WHERE user NOT LIKE 'mysql.%' ORDER BY user")
+	case "mongodb":
+		out, err = mongoEval(name, `db.getSiblingDB("admin").system.users.find().sort({user: 1}).forEach(u =>
+  print(u.user + " (" + u.roles.map(r => r.role + "@" + r.db).join(", ") + ")"))`)
+	case "redis":
+		out, err = redisCommand(name, "ACL", "LIST")
+	case "elasticsearch", "opensearch":
+		return searchUsers(name, engine)
+	}
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+func removeUser(name, engine string, user AppUser) error {
+	switch userEngine(engine) {
+	case "postgresql":
+		return postgresRemoveUser(name, user.Name)
+	case "mysql":
+		_, err := mysqlQuery(name, fmt.Sprintf("DROP USER %s@'%%'", sqlQuote(user.Name)))
+		return err
+	case "mongodb":
+		_, err := mongoEval(name, fmt.Sprintf(`const u = db.getSiblingDB("admin").system.users.findOne({user: %s});
+if (!u) throw new Error("no such user");
+db.getSiblingDB(u.db).dropUser(u.user)`, jsString(user.Name)))
+		return err
+	case "redis":
+		out, err := redisCommand(name, "ACL", "DELUSER", user.Name)
+		if err != nil {
+			return err
+		}
+		if out == "0" {
+			return fmt.Errorf("no such user")
+		}
+		persistRedisACL(name)
+		return nil
+	case "elasticsearch", "opensearch":
+		return searchRemoveUser(name, engine, user.Name)
+	}
+	return nil
+}
+
+// ---- PostgreSQL ----
+
+// postgresUserSQL creates a login role with access to one database, creating
+// the database when it is missing. It is a psql script: \gexec and \connect
+// are psql commands, so it also works from /docker-entrypoint-initdb.d.
+func postgresUserSQL(user AppUser) string {
+	role, database := pgIdent(user.Name), pgIdent(user.Database)
+	var sql strings.Builder
+	fmt.Fprintf(&sql, "CREATE ROLE %s LOGIN PASSWORD %s;\n", role, sqlQuote(user.Password))
+	fmt.Fprintf(&sql, "SELECT %s WHERE NOT EXISTS (SELECT FROM pg_database WHERE datname = %s)\\gexec\n",
+		sqlQuote("CREATE DATABASE "+database), sqlQuote(user.Database))
+	fmt.Fprintf(&sql, "GRANT CONNECT ON DATABASE %s TO %s;\n", database, role)
+	fmt.Fprintf(&sql, "\\connect %s\n", database)
+	if user.ReadOnly {
+		fmt.Fprintf(&sql, "GRANT USAGE ON SCHEMA public TO %s;\n", role)
+		fmt.Fprintf(&sql, "GRANT SELECT ON ALL TABLES IN SCHEMA public TO %s;\n", role)
+		fmt.Fprintf(&sql, "ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT ON TABLES TO %s;\n", role)
+		return sql.String()
+	}
+	fmt.Fprintf(&sql, "GRANT USAGE, CREATE ON SCHEMA public TO %s;\n", role)
+	fmt.Fprintf(&sql, "GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO %s;\n", role)
+	fmt.Fprintf(&sql, "GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO %s;\n", role)
+	fmt.Fprintf(&sql, "ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO %s;\n", role)
+	fmt.Fprintf(&sql, "ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO %s;\n", role)
+	return sql.String()
+}
+
+// postgresRemoveUser drops a role after handing what it owns to the superuser,
+// in every database since ownership and grants are per database
+func postgresRemoveUser(name, role string) error {
+	databases, err := postgresQuery(name, "SELECT datname FROM pg_database WHERE datallowconn ORDER BY datname")
+	if err != nil {
+		return err
+	}
+	for _, database := range strings.Split(databases, "\n") {
+		// postgresClient ends with the database to connect to
+		client := postgresClient(name)
+		client[len(client)-1] = database
+		command := append(client, "-X", "-q", "-v", "ON_ERROR_STOP=1", "-c",
+			fmt.Sprintf("REASSIGN OWNED BY %s TO CURRENT_USER; DROP OWNED BY %s;", pgIdent(role), pgIdent(role)))
+		if _, err := Docker.ExecInContainer(name, nil, command...); err != nil {
+			return err
+		}
+	}
+	_, err = postgresQuery(name, "DROP ROLE "+pgIdent(role))
+	return err
+}
+
+func pgIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// ---- MySQL / MariaDB ----
+
+func mysqlUserSQL(user AppUser) string {
+	privileges := "ALL PRIVILEGES"
+	if user.ReadOnly {
+		privileges = "SELECT, SHOW VIEW"
+	}
+	database := "`" + strings.ReplaceAll(user.Database, "`", "``") + "`"
+	return fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s; CREATE USER %s@'%%' IDENTIFIED BY %s; GRANT %s ON %s.* TO %s@'%%';",
+		database, sqlQuote(user.Name), sqlQuote(user.Password), privileges, database, sqlQuote(user.Name))
+}
+
+// ---- MongoDB ----
+
+func mongoUserJS(user AppUser) string {
+	role := "readWrite"
+	if user.ReadOnly {
+		role = "read"
+	}
+	return fmt.Sprintf(`db.getSiblingDB(%s).createUser({user: %s, pwd: %s, roles: [{role: %s, db: %s}]})`,
+		jsString(user.Database), jsString(user.Name), jsString(user.Password), jsString(role), jsString(user.Database))
+}
+
+func jsString(value string) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// ---- Redis / Valkey / KeyDB ----
+
+// redisACLRules returns the ACL rules of an application user; password is
+// ">plaintext" or "#sha256" (the form config files and command lines take)
+func redisACLRules(user AppUser, password string) []string {
+	keys := "~*"
+	if user.Database != "" {
+		keys = "~" + user.Database + ":*"
+	}
+	rules := []string{"reset", "on", password, keys, "&*"}
+	if user.ReadOnly {
+		return append(rules, "-@all", "+@read", "+@connection")
+	}
+	return append(rules, "+@all", "-@admin", "-@dangerous")
+}
+
+// redisUserArgs returns the server options creating an application user at
+// start. The password is passed hashed so it never shows in the container's command.
+func redisUserArgs(user AppUser) []string {
+	hash := sha256.Sum256([]byte(user.Password))
+	return append([]string{"--user", user.Name}, redisACLRules(user, "#"+hex.EncodeToString(hash[:]))...)
+}
+
+// persistRedisACL saves ACL changes to the ACL or config file when the server has one
+func persistRedisACL(name string) {
+	if _, err := redisCommand(name, "ACL", "SAVE"); err == nil {
+		return
+	}
+	if _, err := redisCommand(name, "CONFIG", "REWRITE"); err == nil {
+		return
+	}
+	fmt.Println("⚠️  The server has no ACL or config file, so the change lasts until the container restarts.")
+}
+
+// ---- Elasticsearch / OpenSearch ----
+
+func searchRequest(name, engine, method, path string, body interface{}) (string, error) {
+	baseURL, auth := searchEndpoint(name, engine)
+	if engine == "elasticsearch" && auth == nil {
+		return "", fmt.Errorf("security is disabled on %s, so it has no users", name)
+	}
+	args := append(auth, "-X", method)
+	if body == nil {
+		return Docker.NetworkCurl(append(args, baseURL+path)...)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, "-H", "Content-Type: application/json", "--data-binary", "@-", baseURL+path)
+	return Docker.NetworkCurlInput(data, args...)
+}
+
+func searchRoleName(user string) string {
+	return "containdb_" + user
+}
+
+func elasticsearchAddUser(name string, user AppUser) error {
+	privileges := []string{"read", "write", "create_index", "view_index_metadata"}
+	if user.ReadOnly {
+		privileges = []string{"read", "view_index_metadata"}
+	}
+	role := map[string]interface{}{
+		"indices": []map[string]interface{}{{"names": []string{user.Database}, "privileges": privileges}},
+	}
+	if _, err := searchRequest(name, "elasticsearch", "PUT", "/_security/role/"+searchRoleName(user.Name), role); err != nil {
+		return err
+	}
+	account := map[string]interface{}{"password": user.Password, "roles": []string{searchRoleName(user.Name)}}
+	_, err := searchRequest(name, "elasticsearch", "PUT", "/_security/user/"+user.Name, account)
+	return err
+}
+
+func opensearchAddUser(name string, user AppUser) error {
+	actions := []string{"crud", "create_index"}
+	if user.ReadOnly {
+		actions = []string{"read"}
+	}
+	const api = "/_plugins/_security/api"
+	role := map[string]interface{}{
+		"index_permissions": []map[string]interface{}{{"index_patterns": []string{user.Database}, "allowed_actions": actions}},
+	}
+	if _, err := searchRequest(name, "opensearch", "PUT", api+"/roles/"+searchRoleName(user.Name), role); err != nil {
+		return err
+	}
+	if _, err := searchRequest(name, "opensearch", "PUT", api+"/internalusers/"+user.Name, map[string]string{"password": user.Password}); err != nil {
+		return err
+	}
+	mapping := map[string][]string{"users": {user.Name}}
+	_, err := searchRequest(name, "opensearch", "PUT", api+"/rolesmapping/"+searchRoleName(user.Name), mapping)
+	return err
+}
+
+func searchUsers(name, engine string) ([]string, error) {
+	path := "/_security/user"
+	if engine == "opensearch" {
+		path = "/_plugins/_security/api/internalusers"
+	}
+	out, err := searchRequest(name, engine, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var users map[string]struct {
+		Roles        []string `json:"roles"`
+		BackendRoles []string `json:"backend_roles"`
+		Reserved     bool     `json:"reserved"`
+	}
+	if err := json.Unmarshal([]byte(out), &users); err != nil {
+		return nil, fmt.Errorf("unexpected response: %s", out)
+	}
+
+	var lines []string
+	for user, info := range users {
+		roles := append(info.Roles, info.BackendRoles...)
+		line := user
+		if len(roles) > 0 {
+			line += " (" + strings.Join(roles, ", ") + ")"
+		}
+		if info.Reserved {
+			line += " [built-in]"
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines, nil
+}
+
+func searchRemoveUser(name, engine, user string) error {
+	paths := []string{"/_security/user/" + user, "/_security/role/" + searchRoleName(user)}
+	if engine == "opensearch" {
+		const api = "/_plugins/_security/api"
+		paths = []string{api + "/internalusers/" + user, api + "/rolesmapping/" + searchRoleName(user), api + "/roles/" + searchRoleName(user)}
+	}
+	if _, err := searchRequest(name, engine, "DELETE", paths[0], nil); err != nil {
+		return err
+	}
+	// Users added elsewhere have no ContainDB role to clean up
+	for _, path := range paths[1:] {
+		_, _ = searchRequest(name, engine, "DELETE", path, nil)
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package base
 
 import (
 	"ContainDB/src/Docker"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // flagHandler handles command line flags for the ContainDB CLI
@@ -20,19 +22,32 @@ func FlagHandler() {
 		fmt.Println("Docker uninstalled successfully! Please restart the terminal or log out & log in again.")
 		os.Exit(0) // Exit after handling flags
 	} else if len(os.Args) > 1 && os.Args[1] == "--export" {
-		fmt.Println("Exporting Docker Compose file with all running services...")
-		fmt.Println("\n⚠️  IMPORTANT: The export functionality only exports container configurations, not the actual data.")
-		fmt.Println("   Even if you used data persistence during installation, the exported compose file only")
-		fmt.Println("   references local volume paths from your current machine which won't exist on other systems.")
-		fmt.Println("   For data backup, please use each database's native backup tools.")
+		exportFlags := flag.NewFlagSet("export", flag.ExitOnError)
+		format := exportFlags.String("format", "compose", "output format: "+strings.Join(ExportFormats, ", "))
+		only := exportFlags.String("only", "", "comma-separated containers to export")
+		exclude := exportFlags.String("exclude", "", "comma-separated containers to leave out")
+		output := exportFlags.String("output", "", "output file (directory for helm)")
+		backup := exportFlags.Bool("backup", false, "back up existing output files instead of refusing to overwrite them")
+		redact := exportFlags.Bool("redact", false, "leave credentials out of compose and env exports")
+		exportFlags.Parse(os.Args[2:])
+		if !containsString(ExportFormats, *format) {
+			fmt.Printf("Error: Unknown export format '%s' (expected one of: %s)\n", *format, strings.Join(ExportFormats, ", "))
+			os.Exit(1)
+		}
 
-		filePath := Docker.MakeDockerComposeWithAllServices()
-		if filePath == "" {
-			fmt.Println("Failed to create Docker Compose file.")
-		} else {
-			fmt.Println("\n✅ Docker Compose file created successfully at:", filePath)
-			fmt.Println("   This file contains only the configuration of your containers.")
+		running, err := Docker.ListRunningDatabases()
+		if err != nil {
+			fmt.Println("Error listing containers:", err)
+			os.Exit(1)
+		}
+		containers, err := Docker.SelectContainers(running, splitList(*only), splitList(*exclude))
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
 		}
+
+		fmt.Printf("Exporting %d service(s) as %s...\n", len(containers), *format)
+		ExportServices(Docker.ExportOptions{Format: *format, Containers: containers, Output: *output, Backup: *backup, Redact: *redact})
 		os.Exit(0) // Exit after handling flags
 	} else if len(os.Args) > 2 && os.Args[1] == "--import" {
 		composeFile := os.Args[2]
@@ -48,7 +63,156 @@ func FlagHandler() {
 		}
 		fmt.Println("Services imported and started successfully!")
 		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 2 && os.Args[1] == "install" {
+		service := os.Args[2]
+		if !IsKnownService(service) {
+			fmt.Printf("Error: Unknown database or tool '%s'\n", service)
+			os.Exit(1)
+		}
+		installFlags := flag.NewFlagSet("install", flag.ExitOnError)
+		file := installFlags.String("file", "", "database file to serve (sqlite, duckdb)")
+		memory := installFlags.String("memory", "", "memory limit, e.g. 2g")
+		cpus := installFlags.String("cpus", "", "CPU limit, e.g. 1.5")
+		shmSize := installFlags.String("shm-size", "", "size of /dev/shm, e.g. 256m")
+		initPath := installFlags.String("init", "", "seed file or directory loaded on first start")
+		appUser := installFlags.String("app-user", "", "application user to create")
+		appPassword := installFlags.String("app-password", "", "password of the application user")
+		appDatabase := installFlags.String("app-db", "", "database (index pattern, key prefix) of the application user")
+		noAuth := installFlags.Bool("no-auth", false, "start without authentication (throwaway development only)")
+		useTLS := installFlags.Bool("tls", false, "serve TLS with a certificate from the ContainDB local CA")
+		bind := installFlags.String("bind", "", "host address published ports bind to, e.g. 0.0.0.0 (default asks, 127.0.0.1 recommended)")
+		installFlags.Parse(os.Args[3:])
+
+		opts := InstallOptions{
+			File:      *file,
+			Resources: ResourceLimits{Memory: *memory, CPUs: *cpus, ShmSize: *shmSize},
+			Init:      *initPath,
+			NoAuth:    *noAuth,
+			Bind:      *bind,
+			TLS:       *useTLS,
+		}
+		if *appUser != "" {
+			if *appPassword == "" {
+				fmt.Println("Error: --app-user needs --app-password")
+				os.Exit(1)
+			}
+			opts.AppUser = &AppUser{Name: *appUser, Password: *appPassword, Database: *appDatabase}
+			if opts.AppUser.Database == "" {
+				opts.AppUser.Database = defaultUserDatabase(service, *appUser)
+			}
+		}
+		InstallService(service, opts)
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "logs" {
+		logFlags := flag.NewFlagSet("logs", flag.ExitOnError)
+		follow := logFlags.Bool("f", false, "follow log output")
+		since := logFlags.String("since", "", "show logs since a duration (e.g. 10m) or timestamp")
+		level := logFlags.String("level", "", "minimum severity: debug, info, warn, error, fatal")
+		positional := parseCommandArgs(logFlags, os.Args[2:])
+		if len(positional) == 0 {
+			fmt.Println("Usage: containdb logs <container> [-f] [--since 10m] [--level error]")
+			os.Exit(1)
+		}
+
+		if err := ViewLogs(positional[0], LogOptions{Follow: *follow, Since: *since, Level: *level}); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "profile" {
+		profileFlags := flag.NewFlagSet("profile", flag.ExitOnError)
+		threshold := profileFlags.Int("threshold", 100, "log queries slower than this many milliseconds")
+		all := profileFlags.Bool("all", false, "log every query, not only slow ones")
+		since := profileFlags.String("since", "10m", "tail: show queries logged since a duration or timestamp")
+		positional := parseCommandArgs(profileFlags, os.Args[2:])
+		if len(positional) != 2 {
+			fmt.Println("Usage: containdb profile <container> on|off|tail [--threshold 100] [--all] [--since 10m]")
+			os.Exit(1)
+		}
+
+		opts := ProfileOptions{ThresholdMs: *threshold, All: *all, Since: *since}
+		if err := ProfileCommand(positional[0], positional[1], opts); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: containdb seed <container> <file|directory>...")
+			os.Exit(1)
+		}
+		if err := SeedCommand(os.Args[2], os.Args[3:]); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "gateway" {
+		if len(os.Args) < 3 {
+			fmt.Println(gatewayUsage)
+			os.Exit(1)
+		}
+		if err := GatewayCommand(os.Args[2:]); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "tls" {
+		if len(os.Args) < 3 {
+			fmt.Println(tlsUsage)
+			os.Exit(1)
+		}
+		if err := TLSCommand(os.Args[2:]); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && os.Args[1] == "user" {
+		userFlags := flag.NewFlagSet("user", flag.ExitOnError)
+		password := userFlags.String("password", "", "password of the new user (asked when omitted)")
+		database := userFlags.String("database", "", "database the user may access (index pattern for Elasticsearch/OpenSearch, key prefix for Redis)")
+		readOnly := userFlags.Bool("read-only", false, "grant read access only")
+		positional := parseCommandArgs(userFlags, os.Args[2:])
+		if len(positional) < 2 {
+			fmt.Println("Usage: containdb user add|list|remove <container> [username] [--password p] [--database db] [--read-only]")
+			os.Exit(1)
+		}
+
+		user := AppUser{Password: *password, Database: *database, ReadOnly: *readOnly}
+		if err := UserCommand(positional[0], positional[1], positional[2:], user); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0) // Exit after handling flags
+	} else if len(os.Args) > 1 && containsString(Docker.LifecycleActions, os.Args[1]) {
+		LifecycleCommand(os.Args[1], os.Args[2:])
+		os.Exit(0) // Exit after handling flags
 	} else if len(os.Args) == 0 {
 		return // No flags to handle, continue with normal execution
 	}
 }
+
+// splitList splits a comma-separated flag value, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCommandArgs parses subcommand flags that may appear before or after the
+// positional arguments (e.g. `logs -f mysql-container` and `logs mysql-container -f`)
+// and returns the positional arguments.
+func parseCommandArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		if flags.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// adminerDrivers maps image name fragments to the Adminer driver used to open them.
+// Adminer reads the driver from the login URL (e.g. ?pgsql=host&username=user).
+var adminerDrivers = []struct {
+	image  string
+	driver string
+}{
+	{"mysql", "server"},
+	{"mariadb", "server"},
+	{"pgvector", "pgsql"},
+	{"postgres", "pgsql"},
+	{"mssql", "mssql"},
+	{"sqlite-web", "sqlite"},
+}
+
+func StartAdminer() {
+	// Check if Adminer is already running
+	if Docker.IsContainerRunning("adminer", true) {
+		fmt.Println("Adminer is already running.")
+		if Docker.AskYesNo("Do you want to remove the existing Adminer container and create a new one?") {
+			fmt.Println("Removing existing Adminer container...")
+			cmd := Docker.Command("rm", "-f", "adminer")
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			if err := cmd.Run(); err != nil {
+				fmt.Println("Error removing Adminer container:", err)
+				return
+			}
+			fmt.Println("Existing Adminer container removed successfully.")
+		} else {
+			fmt.Println("Keeping existing Adminer container. Setup aborted.")
+			return
+		}
+	}
+
+	// Detect local SQL containers of every engine Adminer understands
+	var images []string
+	for _, d := range adminerDrivers {
+		images = append(images, d.image)
+	}
+	sqlContainers := Docker.ListOfContainers(images)
+
+	connectionType := selectConnectionType("Adminer", len(sqlContainers) > 0)
+	if connectionType == "exit" {
+		fmt.Println("Exiting Adminer setup.")
+		return
+	}
+
+	if connectionType == "local" {
+		startAdminerLocal(sqlContainers)
+	} else {
+		startAdminerRemote()
+	}
+}
+
+// adminerDriverFor returns the Adminer driver for a container based on its image
+func adminerDriverFor(containerName string) string {
+	image := Docker.GetContainerImage(containerName)
+	for _, d := range adminerDrivers {
+		if strings.Contains(image, d.image) {
+			return d.driver
+		}
+	}
+	return "server"
+}
+
+// startAdminerLocal links Adminer to a container on ContainDB-Network
+func startAdminerLocal(sqlContainers []string) {
+	items := append(sqlContainers, "Exit")
+	prompt := promptui.Select{
+		Label: "Select a SQL container to link with Adminer",
+		Items: items,
+	}
+	_, selectedContainer, err := prompt.Run()
+	if err != nil {
+		fmt.Println("\n⚠️ Interrupt received, rolling back...")
+		Cleanup()
+		return
+	}
+	if selectedContainer == "Exit" {
+		fmt.Println("Exiting Adminer setup.")
+		return
+	}
+
+	driver := adminerDriverFor(selectedContainer)
+	bind, port := askToolPort("Enter host port to expose Adminer", "8082")
+
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", "ContainDB-Network",
+		"-e", fmt.Sprintf("ADMINER_DEFAULT_SERVER=%s", selectedContainer),
+	}
+
+	// A TLS instance (MySQL requires secure transport) is verified with the local CA
+	files := instanceCAFiles(selectedContainer)
+	if files != nil {
+		pluginPath, env, err := writeAdminerSSLPlugin(driver, true)
+		if err != nil {
+			fmt.Println("Error configuring Adminer SSL:", err)
+			return
+		}
+		defer os.Remove(pluginPath)
+		files["/var/www/html/plugins-enabled/login-ssl.php"] = pluginPath
+		args = append(args, env...)
+	}
+
+	// Pre-fill the login form through the URL: driver, server and default user
+	query := url.Values{}
+	switch driver {
+	case "sqlite":
+		// Adminer opens SQLite files directly, so it needs the same host directory
+		// the sqlite-web container serves, plus a password for the login form.
+		dataDir := Docker.GetContainerMountSource(selectedContainer, "/data")
+		if dataDir == "" {
+			fmt.Println("Error: could not find the database directory of", selectedContainer)
+			return
+		}
+		args = append(args, "-v", fmt.Sprintf("%s:/data", dataDir))
+		dbFile := Docker.GetContainerEnv(selectedContainer, "SQLITE_DATABASE")
+		query.Set("sqlite", "")
+		query.Set("username", "")
+		query.Set("db", "/data/"+dbFile)
+	case "pgsql":
+		user := Docker.GetContainerEnv(selectedContainer, "POSTGRES_USER")
+		if user == "" {
+			user = "postgres"
+		}
+		query.Set(driver, selectedContainer)
+		query.Set("username", user)
+	case "mssql":
+		query.Set(driver, selectedContainer)
+		query.Set("username", "sa")
+	default:
+		query.Set(driver, selectedContainer)
+		query.Set("username", "root")
+	}
+
+	sqlitePassword := ""
+	if driver == "sqlite" {
+		sqlitePassword = AskForInput("Enter a password to protect Adminer's SQLite login", "")
+		if sqlitePassword == "" {
+			fmt.Println("Error: Password cannot be empty.")
+			return
+		}
+	}
+
+	args = append(args, toolPortArgs(bind, port, "8080")...)
+	args = append(args, "adminer")
+
+	fmt.Printf("Pulling Adminer image...\n")
+	cmd := Docker.Command("pull", "adminer")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	_ = cmd.Run()
+
+	if err := runWithFiles("adminer", args, files); err != nil {
+		fmt.Println("Error starting Adminer:", err)
+		return
+	}
+
+	if driver == "sqlite" {
+		if err := enableAdminerPasswordLessLogin(sqlitePassword); err != nil {
+			fmt.Println("Error configuring Adminer SQLite login:", err)
+			return
+		}
+	}
+
+	fmt.Printf("\n✅ Adminer started! Access it at %s/?%s\n", toolURL("adminer", bind, port), query.Encode())
+	fmt.Printf("   Linked to container '%s' (driver: %s)\n", selectedContainer, driver)
+}
+
+// enableAdminerPasswordLessLogin installs Adminer's login-password-less plugin.
+// Adminer refuses password-less databases such as SQLite unless this plugin
+// replaces the check with a password of its own.
+func enableAdminerPasswordLessLogin(password string) error {
+	plugin := fmt.Sprintf(`<?php
+require_once('plugins/login-password-less.php');
+return new AdminerLoginPasswordLess(password_hash(%q, PASSWORD_DEFAULT));
+`, password)
+
+	pluginPath := filepath.Join(Docker.GetTempDir(), "containdb-adminer-login.php")
+	if err := os.WriteFile(pluginPath, []byte(plugin), 0600); err != nil {
+		return err
+	}
+	defer os.Remove(pluginPath)
+
+	cmd := Docker.Command("cp", pluginPath, "adminer:/var/www/html/plugins-enabled/login-password-less.php")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// startAdminerRemote points Adminer at a database outside ContainDB-Network
+func startAdminerRemote() {
+	driverPrompt := promptui.Select{
+		Label: "Select the database engine",
+		Items: []string{"MySQL / MariaDB", "PostgreSQL", "MS SQL Server", "Exit"},
+	}
+	_, engine, err := driverPrompt.Run()
+	if err != nil || engine == "Exit" {
+		fmt.Println("Exiting Adminer setup.")
+		return
+	}
+
+	driver, defaultPort := "server", "3306"
+	switch engine {
+	case "PostgreSQL":
+		driver, defaultPort = "pgsql", "5432"
+	case "MS SQL Server":
+		driver, defaultPort = "mssql", "1433"
+	}
+
+	config := getRemoteTarget(defaultPort, true, false)
+	bind, port := askToolPort("Enter host port to expose Adminer", "8082")
+
+	if config.Host == "" {
+		fmt.Println("Error: Database host cannot be empty")
+		return
+	}
+
+	fmt.Printf("Pulling Adminer image...\n")
+	cmd := Docker.Command("pull", "adminer")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	_ = cmd.Run()
+
+	dbHost, dbPort, network, err := config.connect("adminer")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	server := fmt.Sprintf("%s:%s", dbHost, dbPort)
+
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", network,
+		"-e", fmt.Sprintf("ADMINER_DEFAULT_SERVER=%s", server),
+	}
+
+	files := config.files()
+	if config.EnableSSL {
+		pluginPath, env, err := writeAdminerSSLPlugin(driver, config.CAFile != "")
+		if err != nil {
+			fmt.Println("Error configuring Adminer SSL:", err)
+			return
+		}
+		defer os.Remove(pluginPath)
+		files["/var/www/html/plugins-enabled/login-ssl.php"] = pluginPath
+		args = append(args, env...)
+	}
+	args = append(args, toolPortArgs(bind, port, "8080")...)
+	args = append(args, "adminer")
+
+	if err := runWithFiles("adminer", args, files); err != nil {
+		fmt.Println("Error starting Adminer:", err)
+		return
+	}
+
+	query := url.Values{}
+	query.Set(driver, server)
+	query.Set("username", config.Username)
+	if config.Database != "" {
+		query.Set("db", config.Database)
+	}
+
+	fmt.Printf("\n✅ Adminer started! Access it at %s/?%s\n", toolURL("adminer", bind, port), query.Encode())
+	fmt.Printf("📋 Remote database connection:\n")
+	fmt.Printf("   Host: %s:%s\n", config.Host, config.Port)
+	fmt.Printf("   User: %s\n", config.Username)
+	fmt.Printf("   SSL: %v\n", config.EnableSSL)
+	fmt.Println("   Enter the password on the Adminer login page.")
+}
+
+// writeAdminerSSLPlugin generates the configuration of Adminer's login-ssl plugin
+// for a driver, verifying the server with the CA at remoteCAPath when verify is
+// set. Each driver reads its own options: MySQL takes the CA and ignores the
+// mode, PostgreSQL only passes the sslmode to libpq, which finds the CA through
+// the returned environment.
+func writeAdminerSSLPlugin(driver string, verify bool) (string, []string, error) {
+	var options, env []string
+	switch driver {
+	case "pgsql":
+		options = []string{`"mode" => "require"`}
+		if verify {
+			options = []string{`"mode" => "verify-ca"`}
+			env = []string{"-e", fmt.Sprintf("PGSSLROOTCERT=%s", remoteCAPath)}
+		}
+	case "mssql":
+		options = []string{`"Encrypt" => true`, fmt.Sprintf(`"TrustServerCertificate" => %v`, !verify)}
+	default:
+		options = []string{`"verify" => false`}
+		if verify {
+			options = []string{fmt.Sprintf(`"ca" => %q`, remoteCAPath), `"verify" => true`}
+		}
+	}
+	plugin := fmt.Sprintf(`<?php
+require_once('plugins/login-ssl.php');
+return new AdminerLoginSsl(array(%s));
+`, strings.Join(options, ", "))
+	path, err := writeTempFile("containdb-adminer-ssl-*.php", plugin)
+	return path, env, err
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"ContainDB/src/Docker"
 	"fmt"
+
+	"github.com/manifoldco/promptui"
 )
 
 // AfterContainerToolInstaller provides post-installation setup for database management tools.
@@ -10,10 +12,13 @@ import (
 // For MySQL/MariaDB, it offers to install or reinstall phpMyAdmin. If phpMyAdmin is
 // already running, it asks if the user wants to reinstall it.
 //
-// For MongoDB, it offers to install MongoDB Compass as a GUI management tool.
+// For MongoDB, it offers Mongo Express (web UI in Docker) or MongoDB Compass
+// (desktop app, Debian-family Linux only) as a GUI management tool.
 //
 // For PostgreSQL, it offers to install PgAdmin as a GUI management tool.
 //
+// For Redis, Valkey and KeyDB, it offers to install Redis Insight.
+//
 // Parameters:
 //   - database: A string identifying the database type ("mysql", "mariadb", "mongodb", or "postgresql")
 //
@@ -40,18 +45,29 @@ func AfterContainerToolInstaller(database string) {
 			}
 		}
 	case "mongodb":
-		consentCompass := Docker.AskYesNo("Do you want to install MongoDB Compass?")
-		if consentCompass {
+		toolPrompt := promptui.Select{
+			Label: "Do you want to install a MongoDB management tool?",
+			Items: []string{"Mongo Express (web UI in Docker)", "MongoDB Compass (desktop app)", "No"},
+		}
+		_, choice, err := toolPrompt.Run()
+		if err != nil {
+			fmt.Println("\n⚠️ Interrupt received, rolling back...")
+			Cleanup()
+		}
+		switch choice {
+		case "Mongo Express (web UI in Docker)":
+			StartMongoExpress()
+		case "MongoDB Compass (desktop app)":
 			DownloadMongoDBCompass()
-		} else {
-			fmt.Println("You can install MongoDB Compass later using the 'mongodb compass' option.")
+		default:
+			fmt.Println("You can install Mongo Express or MongoDB Compass later from the NoSQL menu.")
 		}
 	case "postgresql":
 		pgAdminConsent := Docker.AskYesNo("Do you want to install PgAdmin? (yes/no)")
 		if pgAdminConsent {
 			StartPgAdmin()
 		}
-	case "redis":
+	case "redis", "valkey", "keydb":
 		redisInsightConsent := Docker.AskYesNo("Do you want to install Redis Insight? (yes/no)")
 		if redisInsightConsent {
 			StartRedisInsight()
@@ -59,6 +75,24 @@ func AfterContainerToolInstaller(database string) {
 			fmt.Println("You can install Redis Insight later using the 'redis insight' option.")
 		}
 
+	// Embedded database front-ends
+	case "sqlite":
+		fmt.Printf("sqlite-web is serving your database file — access it at http://%s:8080\n", Docker.EngineHost())
+	case "duckdb":
+		fmt.Printf("CloudBeaver is running — access it at http://%s:8978\n", Docker.EngineHost())
+		fmt.Println("After the initial setup, add a DuckDB connection pointing at the file under /data.")
+
+	// Document / key-value store hints
+	case "couchdb":
+		fmt.Printf("Fauxton (CouchDB Web UI) is built-in — access it at http://%s:5984/_utils\n", Docker.EngineHost())
+	case "couchbase":
+		fmt.Printf("Couchbase Web Console is built-in — access it at http://%s:8091\n", Docker.EngineHost())
+	case "dynamodb":
+		fmt.Printf("DynamoDB Local is ready — point the AWS CLI/SDK at it with --endpoint-url http://%s:8000\n", Docker.EngineHost())
+		fmt.Println("Any region and credentials are accepted (e.g. AWS_ACCESS_KEY_ID=local).")
+	case "etcd":
+		fmt.Printf("etcd is ready — try: etcdctl --endpoints=http://%s:2379 put hello world\n", Docker.EngineHost())
+
 	// Vector database tool suggestions
 	case "milvus":
 		if Docker.AskYesNo("Do you want to install Attu (Milvus Web UI)?") {
@@ -85,9 +119,14 @@ func AfterContainerToolInstaller(database string) {
 			fmt.Println("You can install PgAdmin later using the 'PgAdmin' option.")
 		}
 	case "qdrant":
-		fmt.Println("Qdrant Web UI is built-in — access it at http://localhost:6333/dashboard")
+		fmt.Printf("Qdrant Web UI is built-in — access it at http://%s:6333/dashboard\n", Docker.EngineHost())
 	case "redis-stack":
-		fmt.Println("RedisInsight is built-in in Redis Stack — access it at http://localhost:8001")
+		// The UI port is published only when asked for during the install
+		if out, err := Docker.Command("port", "redis-stack-container", "8001").Output(); err == nil && len(out) > 0 {
+			fmt.Printf("RedisInsight is built-in in Redis Stack — access it at http://%s:8001\n", Docker.EngineHost())
+		} else {
+			fmt.Println("RedisInsight is built-in in Redis Stack; publish port 8001 to open it, or install Redis Insight.")
+		}
 
 	default:
 		fmt.Println("No additional tools available for this database type.")
@@ -4,7 +4,6 @@ import (
 	"ContainDB/src/Docker"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 )
 
@@ -13,7 +12,7 @@ func StartAttu() {
 		fmt.Println("Attu container is already running.")
 		if Docker.AskYesNo("Remove existing Attu container and recreate?") {
 			fmt.Println("Removing existing Attu container...")
-			cmd := exec.Command("docker", "rm", "-f", "attu-container")
+			cmd := Docker.Command("rm", "-f", "attu-container")
 			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 			if err := cmd.Run(); err != nil {
 				fmt.Println("Error removing Attu:", err)
@@ -26,10 +25,6 @@ func StartAttu() {
 	}
 
 	milvusContainers := Docker.ListOfContainers([]string{"milvusdb/milvus"})
-	if len(milvusContainers) == 0 {
-		fmt.Println("No running Milvus containers found. Start Milvus first.")
-		return
-	}
 
 	var filtered []string
 	for _, name := range milvusContainers {
@@ -38,6 +33,16 @@ func StartAttu() {
 		}
 	}
 
+	connectionType := selectConnectionType("Attu", len(filtered) > 0)
+	if connectionType == "exit" {
+		fmt.Println("Exiting Attu setup.")
+		return
+	}
+	if connectionType == "remote" {
+		startAttuRemote()
+		return
+	}
+
 	selected := filtered[0]
 	if len(filtered) > 1 {
 		fmt.Println("Select a Milvus container to link with Attu:")
@@ -55,29 +60,77 @@ func StartAttu() {
 		selected = filtered[idx]
 	}
 
-	port := AskForInput("Enter host port for Attu", "3000")
+	bind, port := askToolPort("Enter host port for Attu", "3000")
 
 	fmt.Println("Pulling Attu Docker image...")
-	cmd := exec.Command("docker", "pull", "zilliz/attu:latest")
+	cmd := Docker.Command("pull", "zilliz/attu:latest")
 	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 	_ = cmd.Run()
 
 	fmt.Println("Creating Attu container...")
 	milvusURL := fmt.Sprintf("http://%s:19530", selected)
-	cmd = exec.Command("docker", "run",
-		"-d",
+	args := []string{
+		"run", "-d",
 		"--restart", "unless-stopped",
 		"--network", "ContainDB-Network",
 		"--name", "attu-container",
 		"-e", fmt.Sprintf("MILVUS_URL=%s", milvusURL),
-		"-p", fmt.Sprintf("%s:3000", port),
-		"zilliz/attu:latest",
-	)
+	}
+	args = append(args, toolPortArgs(bind, port, "3000")...)
+	args = append(args, "zilliz/attu:latest")
+	cmd = Docker.Command(args...)
 	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 	if err := cmd.Run(); err != nil {
 		fmt.Println("Error starting Attu:", err)
 	} else {
-		fmt.Printf("✅ Attu started! Access it at http://localhost:%s\n", port)
+		fmt.Printf("✅ Attu started! Access it at %s\n", toolURL("attu-container", bind, port))
 		fmt.Printf("   Connected to Milvus container: %s\n", selected)
+		if Docker.GetContainerEnv(selected, "MILVUS_ROOT_PASSWORD") != "" {
+			fmt.Println("🔐 Log in with user 'root' and the root password chosen when Milvus was installed.")
+		}
+	}
+}
+
+// startAttuRemote points Attu at a remote Milvus instance (e.g. Zilliz Cloud)
+func startAttuRemote() {
+	config := getRemoteTarget("19530", false, true)
+	if config.Host == "" {
+		fmt.Println("Error: Milvus host cannot be empty")
+		return
+	}
+
+	bind, port := askToolPort("Enter host port for Attu", "3000")
+
+	fmt.Println("Pulling Attu Docker image...")
+	cmd := Docker.Command("pull", "zilliz/attu:latest")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	_ = cmd.Run()
+
+	milvusHost, milvusPort, network, err := config.connect("attu-container")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println("Creating Attu container...")
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", network,
+		"-e", fmt.Sprintf("MILVUS_URL=%s://%s:%s", config.scheme(), milvusHost, milvusPort),
+	}
+	if config.CAFile != "" {
+		args = append(args, "-e", fmt.Sprintf("ROOT_CERT_PATH=%s", remoteCAPath))
+	}
+	args = append(args, toolPortArgs(bind, port, "3000")...)
+	args = append(args, "zilliz/attu:latest")
+
+	if err := runWithFiles("attu-container", args, config.files()); err != nil {
+		fmt.Println("Error starting Attu:", err)
+	} else {
+		fmt.Printf("✅ Attu started! Access it at %s\n", toolURL("attu-container", bind, port))
+		fmt.Printf("   Connected to Milvus at: %s:%s\n", config.Host, config.Port)
+		if config.Username != "" {
+			fmt.Printf("   Log in with user '%s' on the Attu connect page.\n", config.Username)
+		}
 	}
 }
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"net"
+
+	"github.com/manifoldco/promptui"
+)
+
+// LoopbackAddress is the interface published ports bind to unless another is chosen
+const LoopbackAddress = "127.0.0.1"
+
+// SelectBindAddress returns the host address published ports bind to, from
+// --bind or by asking. Defaults to this machine only.
+func SelectBindAddress(bind string) (string, error) {
+	if bind != "" {
+		if net.ParseIP(bind) == nil {
+			return "", fmt.Errorf("--bind %s is not an IP address", bind)
+		}
+		warnIfExposed(bind)
+		return bind, nil
+	}
+
+	items := []string{
+		LoopbackAddress + " — this machine only (recommended)",
+		"0.0.0.0 — all interfaces, reachable from the network",
+	}
+	addresses := []string{LoopbackAddress, "0.0.0.0"}
+	// The interfaces of this machine mean nothing to a remote engine
+	if !Docker.IsRemoteEngine() {
+		for _, iface := range Docker.LocalInterfaces() {
+			items = append(items, fmt.Sprintf("%s — %s only", iface.IP, iface.Name))
+			addresses = append(addresses, iface.IP)
+		}
+	}
+	items = append(items, "Other address")
+
+	prompt := promptui.Select{
+		Label: "Bind published ports to",
+		Items: items,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	address := ""
+	if index < len(addresses) {
+		address = addresses[index]
+	} else {
+		address = AskForInput("Enter the host address to bind to", LoopbackAddress)
+		if net.ParseIP(address) == nil {
+			return "", fmt.Errorf("%s is not an IP address", address)
+		}
+	}
+	warnIfExposed(address)
+	return address, nil
+}
+
+func warnIfExposed(address string) {
+	if isLoopback(address) {
+		if Docker.IsRemoteEngine() {
+			fmt.Printf("ℹ️  Ports bound to %s are reachable on %s only; tunnel to them with `ssh -L <port>:%s:<port> %s`.\n",
+				address, Docker.EngineHost(), address, Docker.EngineHost())
+		}
+		return
+	}
+	fmt.Printf("⚠️  Ports bound to %s are reachable from other machines; keep authentication on and the firewall in mind.\n", address)
+}
+
+// ConnectHost returns the host name clients reach a port bound to address at
+func ConnectHost(address string) string {
+	if address == LoopbackAddress || address == "0.0.0.0" {
+		return Docker.EngineHost()
+	}
+	return address
+}
+
+// isLoopback reports whether address only accepts connections from this machine
+func isLoopback(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
+}
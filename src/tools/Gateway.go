@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// GatewayContainer is the reverse proxy serving the management tools over
+// HTTPS at <tool>.containdb.localhost
+const GatewayContainer = "containdb-gateway"
+
+// GatewayDomain is the domain the gateway serves; browsers resolve *.localhost to this machine
+const GatewayDomain = "containdb.localhost"
+
+const gatewayImage = "caddy:2-alpine"
+
+// gatewayRoute is a tool served by the gateway: its host name under
+// GatewayDomain, container and web port
+type gatewayRoute struct {
+	Host      string
+	Container string
+	Port      string
+}
+
+var gatewayRoutes = []gatewayRoute{
+	{"phpmyadmin", "phpmyadmin", "80"},
+	{"pgadmin", "pgadmin", "80"},
+	{"adminer", "adminer", "8080"},
+	{"redisinsight", "redisinsight", "5540"},
+	{"mongo-express", "mongo-express", "8081"},
+	{"kibana", "kibana-container", "5601"},
+	{"opensearch-dashboards", "opensearch-dashboards-container", "5601"},
+	{"attu", "attu-container", "3000"},
+}
+
+// GatewayRunning reports whether the gateway container is up
+func GatewayRunning() bool {
+	return Docker.IsContainerRunning(GatewayContainer, true)
+}
+
+// StartGateway (re)creates the gateway, publishing its HTTPS port on
+// bindAddress:port with a certificate from the local CA
+func StartGateway(bindAddress, port string) error {
+	ca, err := Docker.LoadLocalCA()
+	if err != nil {
+		return err
+	}
+	hosts := []string{GatewayDomain, "*." + GatewayDomain}
+	for _, route := range gatewayRoutes {
+		hosts = append(hosts, route.Host+"."+GatewayDomain)
+	}
+	certPEM, keyPEM, err := ca.IssueServerCert(GatewayDomain, hosts)
+	if err != nil {
+		return err
+	}
+
+	_ = Docker.Command("rm", "-f", GatewayContainer).Run()
+
+	fmt.Println("Pulling Caddy Docker image...")
+	cmd := Docker.Command("pull", gatewayImage)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	_ = cmd.Run()
+
+	args := []string{"create", "--name", GatewayContainer,
+		"--restart", "unless-stopped",
+		"--network", "ContainDB-Network",
+		"-p", net.JoinHostPort(bindAddress, port) + ":443",
+		gatewayImage,
+	}
+	fmt.Println("Running:", Docker.RuntimeName(), strings.Join(args, " "))
+	cmd = Docker.Command(args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	// The image runs /etc/caddy/Caddyfile; it is replaced before the start
+	files := []Docker.ContainerFile{
+		{Path: "/etc/caddy/Caddyfile", Content: []byte(gatewayCaddyfile()), Mode: 0644},
+		{Path: "/etc/caddy/certs/gateway.pem", Content: certPEM, Mode: 0644},
+		{Path: "/etc/caddy/certs/gateway-key.pem", Content: keyPEM, Mode: 0600},
+	}
+	if err := Docker.CopyFilesToContainer(GatewayContainer, files); err != nil {
+		_ = Docker.Command("rm", "-f", GatewayContainer).Run()
+		return err
+	}
+	cmd = Docker.Command("start", GatewayContainer)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// gatewayCaddyfile routes each tool's host name to its container on
+// ContainDB-Network. Upstreams are resolved per request, so tools installed
+// later are served without reloading.
+func gatewayCaddyfile() string {
+	var config strings.Builder
+	config.WriteString("{\n\tadmin off\n\tauto_https disable_redirects\n}\n")
+	for _, route := range gatewayRoutes {
+		fmt.Fprintf(&config, "\nhttps://%s.%s {\n", route.Host, GatewayDomain)
+		config.WriteString("\ttls /etc/caddy/certs/gateway.pem /etc/caddy/certs/gateway-key.pem\n")
+		fmt.Fprintf(&config, "\treverse_proxy %s:%s\n}\n", route.Container, route.Port)
+	}
+	return config.String()
+}
+
+// StopGateway removes the gateway container; tools keep running
+func StopGateway() error {
+	if out, err := Docker.Command("rm", "-f", GatewayContainer).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove %s: %v %s", GatewayContainer, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// GatewayURL returns the address the gateway serves a tool container at,
+// "" when the tool has no route
+func GatewayURL(container string) string {
+	for _, route := range gatewayRoutes {
+		if route.Container != container {
+			continue
+		}
+		address := route.Host + "." + GatewayDomain
+		if port := gatewayPort(); port != "" && port != "443" {
+			address += ":" + port
+		}
+		return "https://" + address
+	}
+	return ""
+}
+
+// gatewayPort returns the host port the gateway is published on
+func gatewayPort() string {
+	_, port := gatewayBinding()
+	return port
+}
+
+// gatewayBinding returns the host address and port the gateway is published on
+func gatewayBinding() (string, string) {
+	out, err := Docker.Command("port", GatewayContainer, "443").Output()
+	if err != nil {
+		return "", ""
+	}
+	// e.g. "127.0.0.1:443", one line per address family
+	line := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+	index := strings.LastIndex(line, ":")
+	if index < 0 {
+		return "", ""
+	}
+	return strings.Trim(line[:index], "[]"), line[index+1:]
+}
+
+// GatewayTools returns the running tool containers and their gateway URLs
+func GatewayTools() [][2]string {
+	var found [][2]string
+	for _, route := range gatewayRoutes {
+		if Docker.IsContainerRunning(route.Container, true) {
+			found = append(found, [2]string{route.Container, GatewayURL(route.Container)})
+		}
+	}
+	return found
+}
+
+// askToolPort asks the host address and port a tool is published on, the
+// address defaulting to this machine only. Behind the gateway a tool needs no
+// host port, and "" is returned for both.
+func askToolPort(label, defaultPort string) (string, string) {
+	if GatewayRunning() {
+		fmt.Println("🌐 The ContainDB gateway is running; the tool will be served over HTTPS without a host port.")
+		return "", ""
+	}
+	port := AskForInput(label, defaultPort)
+	bind, err := SelectBindAddress("")
+	if err != nil {
+		fmt.Printf("⚠️  %v; binding to %s\n", err, LoopbackAddress)
+		bind = LoopbackAddress
+	}
+	return bind, port
+}
+
+// toolPortArgs returns the -p arguments publishing a tool, none behind the gateway
+func toolPortArgs(bind, port, containerPort string) []string {
+	if port == "" {
+		return nil
+	}
+	return []string{"-p", net.JoinHostPort(bind, port) + ":" + containerPort}
+}
+
+// toolURL returns where a started tool is served. Behind the gateway the tool
+// joins ContainDB-Network first, in case it runs on another network.
+func toolURL(container, bind, port string) string {
+	if port != "" {
+		return "http://" + net.JoinHostPort(ConnectHost(bind), port)
+	}
+	// Fails harmlessly when the tool is already connected
+	_ = Docker.Command("network", "connect", "ContainDB-Network", container).Run()
+	return GatewayURL(container)
+}
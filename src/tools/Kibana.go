@@ -4,7 +4,6 @@ import (
 	"ContainDB/src/Docker"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 )
 
@@ -13,7 +12,7 @@ func StartKibana() {
 		fmt.Println("Kibana container is already running.")
 		if Docker.AskYesNo("Remove existing Kibana container and recreate?") {
 			fmt.Println("Removing existing Kibana container...")
-			cmd := exec.Command("docker", "rm", "-f", "kibana-container")
+			cmd := Docker.Command("rm", "-f", "kibana-container")
 			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 			if err := cmd.Run(); err != nil {
 				fmt.Println("Error removing Kibana:", err)
@@ -26,10 +25,6 @@ func StartKibana() {
 	}
 
 	esContainers := Docker.ListOfContainers([]string{"elasticsearch"})
-	if len(esContainers) == 0 {
-		fmt.Println("No running Elasticsearch containers found. Start Elasticsearch first.")
-		return
-	}
 
 	var filtered []string
 	for _, name := range esContainers {
@@ -38,6 +33,16 @@ func StartKibana() {
 		}
 	}
 
+	connectionType := selectConnectionType("Kibana", len(filtered) > 0)
+	if connectionType == "exit" {
+		fmt.Println("Exiting Kibana setup.")
+		return
+	}
+	if connectionType == "remote" {
+		startKibanaRemote()
+		return
+	}
+
 	selected := filtered[0]
 	if len(filtered) > 1 {
 		fmt.Println("Select an Elasticsearch container to link with Kibana:")
@@ -55,29 +60,83 @@ func StartKibana() {
 		selected = filtered[idx]
 	}
 
-	port := AskForInput("Enter host port for Kibana", "5601")
+	bind, port := askToolPort("Enter host port for Kibana", "5601")
 
 	fmt.Println("Pulling Kibana Docker image...")
-	cmd := exec.Command("docker", "pull", "kibana:latest")
+	cmd := Docker.Command("pull", "kibana:latest")
 	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 	_ = cmd.Run()
 
 	fmt.Println("Creating Kibana container...")
-	esHosts := fmt.Sprintf("http://%s:9200", selected)
-	cmd = exec.Command("docker", "run",
-		"-d",
+	scheme := "http"
+	args := []string{
 		"--restart", "unless-stopped",
 		"--network", "ContainDB-Network",
-		"--name", "kibana-container",
-		"-e", fmt.Sprintf("ELASTICSEARCH_HOSTS=%s", esHosts),
-		"-p", fmt.Sprintf("%s:5601", port),
-		"kibana:latest",
-	)
-	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
-	if err := cmd.Run(); err != nil {
+	}
+	// A TLS instance serves HTTPS with a certificate naming the container
+	files := instanceCAFiles(selected)
+	if files != nil {
+		scheme = "https"
+		args = append(args, "-e", fmt.Sprintf("ELASTICSEARCH_SSL_CERTIFICATEAUTHORITIES=%s", remoteCAPath))
+		fmt.Println("ℹ️  TLS Elasticsearch runs with security enabled; Kibana 8+ then needs the 'kibana_system' user, which the remote option asks for.")
+	}
+	args = append(args, "-e", fmt.Sprintf("ELASTICSEARCH_HOSTS=%s://%s:9200", scheme, selected))
+	args = append(args, toolPortArgs(bind, port, "5601")...)
+	args = append(args, "kibana:latest")
+	if err := runWithFiles("kibana-container", args, files); err != nil {
 		fmt.Println("Error starting Kibana:", err)
 	} else {
-		fmt.Printf("✅ Kibana started! Access it at http://localhost:%s\n", port)
+		fmt.Printf("✅ Kibana started! Access it at %s\n", toolURL("kibana-container", bind, port))
 		fmt.Printf("   Connected to Elasticsearch container: %s\n", selected)
 	}
 }
+
+// startKibanaRemote points Kibana at a remote Elasticsearch cluster (e.g. Elastic Cloud)
+func startKibanaRemote() {
+	fmt.Println("ℹ️  Kibana 8+ cannot log in as the 'elastic' superuser; use the 'kibana_system' user or similar.")
+	config := getRemoteTarget("9200", false, true)
+	if config.Host == "" {
+		fmt.Println("Error: Elasticsearch host cannot be empty")
+		return
+	}
+
+	bind, port := askToolPort("Enter host port for Kibana", "5601")
+
+	fmt.Println("Pulling Kibana Docker image...")
+	cmd := Docker.Command("pull", "kibana:latest")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	_ = cmd.Run()
+
+	esHost, esPort, network, err := config.connect("kibana-container")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println("Creating Kibana container...")
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", network,
+		"-e", fmt.Sprintf("ELASTICSEARCH_HOSTS=%s://%s:%s", config.scheme(), esHost, esPort),
+	}
+	if config.Username != "" {
+		args = append(args,
+			"-e", fmt.Sprintf("ELASTICSEARCH_USERNAME=%s", config.Username),
+			"-e", fmt.Sprintf("ELASTICSEARCH_PASSWORD=%s", config.Password))
+	}
+	if config.EnableSSL {
+		args = append(args, "-e", fmt.Sprintf("ELASTICSEARCH_SSL_VERIFICATIONMODE=%s", config.verificationMode()))
+		if config.CAFile != "" {
+			args = append(args, "-e", fmt.Sprintf("ELASTICSEARCH_SSL_CERTIFICATEAUTHORITIES=%s", remoteCAPath))
+		}
+	}
+	args = append(args, toolPortArgs(bind, port, "5601")...)
+	args = append(args, "kibana:latest")
+
+	if err := runWithFiles("kibana-container", args, config.files()); err != nil {
+		fmt.Println("Error starting Kibana:", err)
+	} else {
+		fmt.Printf("✅ Kibana started! Access it at %s\n", toolURL("kibana-container", bind, port))
+		fmt.Printf("   Connected to Elasticsearch at: %s:%s\n", config.Host, config.Port)
+	}
+}
@@ -23,6 +23,15 @@ func DownloadMongoDBCompass() {
 		case "darwin":
 			fmt.Println("macOS: Download from https://www.mongodb.com/try/download/compass or use Homebrew: brew install mongodb-compass")
 		}
+		fmt.Println("Alternatively, use 'Mongo Express' for a web UI that runs in Docker on any OS.")
+		return
+	}
+
+	// The Compass package is a .deb, so only Debian-family distributions can install it
+	if _, err := exec.LookPath("dpkg"); err != nil {
+		fmt.Println("⚠️  MongoDB Compass can only be installed automatically on Debian-family Linux (dpkg not found).")
+		fmt.Println("Download it manually from https://www.mongodb.com/try/download/compass")
+		fmt.Println("or use 'Mongo Express' for a web UI that runs in Docker.")
 		return
 	}
 
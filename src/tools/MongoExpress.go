@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// StartMongoExpress runs Mongo Express, a web UI for MongoDB, linked to a MongoDB
+// container on ContainDB-Network. Unlike MongoDB Compass it works on any host
+// that runs Docker.
+func StartMongoExpress() {
+	if Docker.IsContainerRunning("mongo-express", true) {
+		fmt.Println("Mongo Express is already running.")
+		if Docker.AskYesNo("Do you want to remove the existing Mongo Express container and create a new one?") {
+			fmt.Println("Removing existing Mongo Express container...")
+			cmd := Docker.Command("rm", "-f", "mongo-express")
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				fmt.Println("Error removing Mongo Express container:", err)
+				return
+			}
+			fmt.Println("Existing Mongo Express container removed successfully.")
+		} else {
+			fmt.Println("Keeping existing Mongo Express container. Setup aborted.")
+			return
+		}
+	}
+
+	// The "mongo" image filter also matches the mongo-express image itself
+	var mongoContainers []string
+	for _, name := range Docker.ListOfContainers([]string{"mongo"}) {
+		if name != "mongo-express" {
+			mongoContainers = append(mongoContainers, name)
+		}
+	}
+	if len(mongoContainers) == 0 {
+		fmt.Println("No running MongoDB containers found. Start MongoDB first.")
+		return
+	}
+
+	items := append(mongoContainers, "Exit")
+	prompt := promptui.Select{
+		Label: "Select a MongoDB container to link with Mongo Express",
+		Items: items,
+	}
+	_, selected, err := prompt.Run()
+	if err != nil {
+		fmt.Println("\n⚠️ Interrupt received, rolling back...")
+		Cleanup()
+		return
+	}
+	if selected == "Exit" {
+		fmt.Println("Exiting Mongo Express setup.")
+		return
+	}
+
+	bind, port := askToolPort("Enter host port for Mongo Express", "8081")
+	fmt.Println("Mongo Express is protected with basic auth.")
+	webUser := AskForInput("Enter Mongo Express username", "admin")
+	webPass := AskForInput("Enter Mongo Express password", "")
+	if webPass == "" {
+		fmt.Println("Error: Password cannot be empty.")
+		return
+	}
+
+	// Reuse the root credentials of the MongoDB container when it has auth enabled
+	mongoURL := url.URL{Scheme: "mongodb", Host: selected + ":27017", Path: "/"}
+	rootUser := Docker.GetContainerEnv(selected, "MONGO_INITDB_ROOT_USERNAME")
+	rootPass := Docker.GetContainerEnv(selected, "MONGO_INITDB_ROOT_PASSWORD")
+	query := url.Values{}
+	if rootUser != "" {
+		mongoURL.User = url.UserPassword(rootUser, rootPass)
+		query.Set("authSource", "admin")
+	}
+	// A TLS instance runs with requireTLS; its certificate names the container
+	files := instanceCAFiles(selected)
+	if files != nil {
+		query.Set("tls", "true")
+		query.Set("tlsCAFile", remoteCAPath)
+	}
+	mongoURL.RawQuery = query.Encode()
+
+	fmt.Println("Pulling Mongo Express Docker image...")
+	cmd := Docker.Command("pull", "mongo-express:latest")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	_ = cmd.Run()
+
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", "ContainDB-Network",
+		"-e", fmt.Sprintf("ME_CONFIG_MONGODB_URL=%s", mongoURL.String()),
+		"-e", "ME_CONFIG_BASICAUTH=true",
+		"-e", fmt.Sprintf("ME_CONFIG_BASICAUTH_USERNAME=%s", webUser),
+		"-e", fmt.Sprintf("ME_CONFIG_BASICAUTH_PASSWORD=%s", webPass),
+	}
+	args = append(args, toolPortArgs(bind, port, "8081")...)
+	args = append(args, "mongo-express:latest")
+
+	fmt.Println("Creating Mongo Express container...")
+	if err := runWithFiles("mongo-express", args, files); err != nil {
+		fmt.Println("Error starting Mongo Express:", err)
+	} else {
+		fmt.Printf("✅ Mongo Express started! Access it at %s\n", toolURL("mongo-express", bind, port))
+		fmt.Printf("   Connected to MongoDB container: %s\n", selected)
+		fmt.Printf("🔐 Login: %s / %s\n", webUser, strings.Repeat("*", len(webPass)))
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"ContainDB/src/Docker"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 )
 
@@ -13,7 +12,7 @@ func StartOpenSearchDashboards() {
 		fmt.Println("OpenSearch Dashboards container is already running.")
 		if Docker.AskYesNo("Remove existing OpenSearch Dashboards container and recreate?") {
 			fmt.Println("Removing existing OpenSearch Dashboards container...")
-			cmd := exec.Command("docker", "rm", "-f", "opensearch-dashboards-container")
+			cmd := Docker.Command("rm", "-f", "opensearch-dashboards-container")
 			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 			if err := cmd.Run(); err != nil {
 				fmt.Println("Error removing OpenSearch Dashboards:", err)
@@ -26,10 +25,6 @@ func StartOpenSearchDashboards() {
 	}
 
 	osContainers := Docker.ListOfContainers([]string{"opensearchproject/opensearch"})
-	if len(osContainers) == 0 {
-		fmt.Println("No running OpenSearch containers found. Start OpenSearch first.")
-		return
-	}
 
 	var filtered []string
 	for _, name := range osContainers {
@@ -38,6 +33,16 @@ func StartOpenSearchDashboards() {
 		}
 	}
 
+	connectionType := selectConnectionType("OpenSearch Dashboards", len(filtered) > 0)
+	if connectionType == "exit" {
+		fmt.Println("Exiting OpenSearch Dashboards setup.")
+		return
+	}
+	if connectionType == "remote" {
+		startOpenSearchDashboardsRemote()
+		return
+	}
+
 	selected := filtered[0]
 	if len(filtered) > 1 {
 		fmt.Println("Select an OpenSearch container to link with OpenSearch Dashboards:")
@@ -55,29 +60,80 @@ func StartOpenSearchDashboards() {
 		selected = filtered[idx]
 	}
 
-	port := AskForInput("Enter host port for OpenSearch Dashboards", "5601")
+	bind, port := askToolPort("Enter host port for OpenSearch Dashboards", "5601")
 
 	fmt.Println("Pulling OpenSearch Dashboards Docker image...")
-	cmd := exec.Command("docker", "pull", "opensearchproject/opensearch-dashboards:latest")
+	cmd := Docker.Command("pull", "opensearchproject/opensearch-dashboards:latest")
 	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 	_ = cmd.Run()
 
 	fmt.Println("Creating OpenSearch Dashboards container...")
 	osHosts := fmt.Sprintf("http://%s:9200", selected)
-	cmd = exec.Command("docker", "run",
-		"-d",
+	args := []string{
+		"run", "-d",
 		"--restart", "unless-stopped",
 		"--network", "ContainDB-Network",
 		"--name", "opensearch-dashboards-container",
 		"-e", fmt.Sprintf("OPENSEARCH_HOSTS=%s", osHosts),
-		"-p", fmt.Sprintf("%s:5601", port),
-		"opensearchproject/opensearch-dashboards:latest",
-	)
+	}
+	args = append(args, toolPortArgs(bind, port, "5601")...)
+	args = append(args, "opensearchproject/opensearch-dashboards:latest")
+	cmd = Docker.Command(args...)
 	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 	if err := cmd.Run(); err != nil {
 		fmt.Println("Error starting OpenSearch Dashboards:", err)
 	} else {
-		fmt.Printf("✅ OpenSearch Dashboards started! Access it at http://localhost:%s\n", port)
+		fmt.Printf("✅ OpenSearch Dashboards started! Access it at %s\n", toolURL("opensearch-dashboards-container", bind, port))
 		fmt.Printf("   Connected to OpenSearch container: %s\n", selected)
 	}
 }
+
+// startOpenSearchDashboardsRemote points OpenSearch Dashboards at a remote cluster
+// (e.g. Amazon OpenSearch Service)
+func startOpenSearchDashboardsRemote() {
+	config := getRemoteTarget("9200", false, true)
+	if config.Host == "" {
+		fmt.Println("Error: OpenSearch host cannot be empty")
+		return
+	}
+
+	bind, port := askToolPort("Enter host port for OpenSearch Dashboards", "5601")
+
+	fmt.Println("Pulling OpenSearch Dashboards Docker image...")
+	cmd := Docker.Command("pull", "opensearchproject/opensearch-dashboards:latest")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	_ = cmd.Run()
+
+	osHost, osPort, network, err := config.connect("opensearch-dashboards-container")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println("Creating OpenSearch Dashboards container...")
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", network,
+		"-e", fmt.Sprintf("OPENSEARCH_HOSTS=%s://%s:%s", config.scheme(), osHost, osPort),
+	}
+	if config.Username != "" {
+		args = append(args,
+			"-e", fmt.Sprintf("OPENSEARCH_USERNAME=%s", config.Username),
+			"-e", fmt.Sprintf("OPENSEARCH_PASSWORD=%s", config.Password))
+	}
+	if config.EnableSSL {
+		args = append(args, "-e", fmt.Sprintf("OPENSEARCH_SSL_VERIFICATIONMODE=%s", config.verificationMode()))
+		if config.CAFile != "" {
+			args = append(args, "-e", fmt.Sprintf("OPENSEARCH_SSL_CERTIFICATEAUTHORITIES=%s", remoteCAPath))
+		}
+	}
+	args = append(args, toolPortArgs(bind, port, "5601")...)
+	args = append(args, "opensearchproject/opensearch-dashboards:latest")
+
+	if err := runWithFiles("opensearch-dashboards-container", args, config.files()); err != nil {
+		fmt.Println("Error starting OpenSearch Dashboards:", err)
+	} else {
+		fmt.Printf("✅ OpenSearch Dashboards started! Access it at %s\n", toolURL("opensearch-dashboards-container", bind, port))
+		fmt.Printf("   Connected to OpenSearch at: %s:%s\n", config.Host, config.Port)
+	}
+}
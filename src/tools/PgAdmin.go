@@ -2,9 +2,9 @@ package tools
 
 import (
 	"ContainDB/src/Docker"
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/manifoldco/promptui"
@@ -16,7 +16,7 @@ func StartPgAdmin() {
 		fmt.Println("pgAdmin container is already running.")
 		if Docker.AskYesNo("Remove existing pgAdmin container and recreate?") {
 			fmt.Println("Removing existing pgAdmin container...")
-			cmd := exec.Command("docker", "rm", "-f", "pgadmin")
+			cmd := Docker.Command("rm", "-f", "pgadmin")
 			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 			if err := cmd.Run(); err != nil {
 				fmt.Println("Error removing pgAdmin:", err)
@@ -29,12 +29,8 @@ func StartPgAdmin() {
 		}
 	}
 
-	// 2️⃣ List running SQL containers to link with
-	networks := Docker.ListOfContainers([]string{"postgres"})
-	if len(networks) == 0 {
-		fmt.Println("No running SQL containers (Postgres/MySQL/MariaDB) found.")
-		return
-	}
+	// 2️⃣ List running Postgres containers to link with
+	networks := Docker.ListOfContainers([]string{"postgres", "pgvector"})
 
 	// Remove pgAdmin and phpMyAdmin from the list if they exist
 	var filteredNetworks []string
@@ -44,6 +40,20 @@ func StartPgAdmin() {
 		}
 	}
 
+	connectionType := selectConnectionType("pgAdmin", len(filteredNetworks) > 0)
+	if connectionType == "exit" {
+		fmt.Println("Exiting pgAdmin setup.")
+		return
+	}
+	if connectionType == "local" {
+		startPgAdminLocal(filteredNetworks)
+	} else {
+		startPgAdminRemote()
+	}
+}
+
+// startPgAdminLocal links pgAdmin to a Postgres container on ContainDB-Network
+func startPgAdminLocal(filteredNetworks []string) {
 	items := append(filteredNetworks, "Exit")
 	prompt := promptui.Select{
 		Label: "Select a DB container to link with pgAdmin",
@@ -60,37 +70,36 @@ func StartPgAdmin() {
 	}
 
 	// 3️⃣ Ask port and credentials
-	port := AskForInput("Enter host port for pgAdmin (e.g. 5050)", "5050")
+	bind, port := askToolPort("Enter host port for pgAdmin (e.g. 5050)", "5050")
 	email := AskForInput("Enter PGADMIN_DEFAULT_EMAIL", "admin@local.com")
 	password := AskForInput("Enter PGADMIN_DEFAULT_PASSWORD", "")
 
 	// 4️⃣ Pull image
 	fmt.Println("Pulling pgAdmin Docker image...")
-	cmd := exec.Command("docker", "pull", "dpage/pgadmin4:latest")
+	cmd := Docker.Command("pull", "dpage/pgadmin4:latest")
 	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
 	_ = cmd.Run()
 
 	// 5️⃣ Run container
 	fmt.Println("Creating pgAdmin container...")
-	cmd = exec.Command("docker", "run",
-		"-d",
+	args := []string{
 		"--restart", "unless-stopped",
 		"--network", "ContainDB-Network",
-		"--name", "pgadmin",
 		"-e", fmt.Sprintf("PGADMIN_DEFAULT_EMAIL=%s", email),
 		"-e", fmt.Sprintf("PGADMIN_DEFAULT_PASSWORD=%s", password),
-		"-p", fmt.Sprintf("%s:80", port),
-		"dpage/pgadmin4:latest",
-	)
-	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
-	if err := cmd.Run(); err != nil {
+	}
+	args = append(args, toolPortArgs(bind, port, "80")...)
+	args = append(args, "dpage/pgadmin4:latest")
+	// A TLS instance also accepts plaintext; the CA lets pgAdmin verify it
+	files := instanceCAFiles(selected)
+	if err := runWithFiles("pgadmin", args, files); err != nil {
 		fmt.Println("Error starting pgAdmin:", err)
 	} else {
-		fmt.Printf("✅ pgAdmin started! Access it at http://localhost:%s\n", port)
+		fmt.Printf("✅ pgAdmin started! Access it at %s\n", toolURL("pgadmin", bind, port))
 
 		// Get container IP address
 		containerIP := ""
-		ipCmd := exec.Command("docker", "inspect", "-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", selected)
+		ipCmd := Docker.Command("inspect", "-f", "{{range .NetworkSettings.Networks}}{{.IPAddress}}{{end}}", selected)
 		ipOutput, err := ipCmd.Output()
 		if err == nil {
 			containerIP = string(ipOutput)
@@ -104,9 +113,111 @@ func StartPgAdmin() {
 			fmt.Printf("   - Container name: %s\n", selected)
 			fmt.Printf("   - IP Address: %s\n", containerIP)
 			fmt.Printf("   - Port: 5432\n")
+			if files != nil {
+				fmt.Printf("   - SSL mode: verify-full, root certificate: %s\n", remoteCAPath)
+			}
 		}
 		fmt.Printf("🔐 pgAdmin login credentials:\n")
 		fmt.Printf("   - Email: %s\n", email)
 		fmt.Printf("   - Password: %s\n", password)
 	}
 }
+
+// startPgAdminRemote points pgAdmin at a remote Postgres server (e.g. RDS or Cloud SQL)
+// by registering it in a pre-loaded server list
+func startPgAdminRemote() {
+	config := getRemoteTarget("5432", true, true)
+	if config.Host == "" {
+		fmt.Println("Error: Database host cannot be empty")
+		return
+	}
+
+	bind, port := askToolPort("Enter host port for pgAdmin (e.g. 5050)", "5050")
+	email := AskForInput("Enter PGADMIN_DEFAULT_EMAIL", "admin@local.com")
+	password := AskForInput("Enter PGADMIN_DEFAULT_PASSWORD", "")
+
+	fmt.Println("Pulling pgAdmin Docker image...")
+	cmd := Docker.Command("pull", "dpage/pgadmin4:latest")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	_ = cmd.Run()
+
+	dbHost, dbPort, network, err := config.connect("pgadmin")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	serversFile, err := writePgAdminServers(config, dbHost, dbPort)
+	if err != nil {
+		fmt.Println("Error preparing pgAdmin server list:", err)
+		return
+	}
+	defer os.Remove(serversFile)
+
+	files := config.files()
+	files["/tmp/containdb-servers.json"] = serversFile
+
+	fmt.Println("Creating pgAdmin container...")
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", network,
+		"-e", fmt.Sprintf("PGADMIN_DEFAULT_EMAIL=%s", email),
+		"-e", fmt.Sprintf("PGADMIN_DEFAULT_PASSWORD=%s", password),
+		"-e", "PGADMIN_SERVER_JSON_FILE=/tmp/containdb-servers.json",
+	}
+	args = append(args, toolPortArgs(bind, port, "80")...)
+	args = append(args, "dpage/pgadmin4:latest")
+	if err := runWithFiles("pgadmin", args, files); err != nil {
+		fmt.Println("Error starting pgAdmin:", err)
+		return
+	}
+
+	fmt.Printf("✅ pgAdmin started! Access it at %s\n", toolURL("pgadmin", bind, port))
+	fmt.Printf("📋 Server '%s' is pre-registered (enter the database password on first connect).\n", config.Host)
+	fmt.Printf("🔐 pgAdmin login credentials:\n")
+	fmt.Printf("   - Email: %s\n", email)
+	fmt.Printf("   - Password: %s\n", password)
+}
+
+// writePgAdminServers generates the servers.json file pgAdmin imports on first start
+func writePgAdminServers(config RemoteTarget, dbHost, dbPort string) (string, error) {
+	sslMode := "prefer"
+	if config.EnableSSL {
+		sslMode = "require"
+	}
+	params := map[string]interface{}{"sslmode": sslMode, "connect_timeout": 10}
+	if config.CAFile != "" {
+		// Through a tunnel the host name no longer matches the certificate
+		params["sslmode"] = "verify-full"
+		if config.SSHTunnel != nil {
+			params["sslmode"] = "verify-ca"
+		}
+		params["sslrootcert"] = remoteCAPath
+	}
+
+	maintenanceDB := config.Database
+	if maintenanceDB == "" {
+		maintenanceDB = "postgres"
+	}
+	portNumber := 5432
+	fmt.Sscanf(dbPort, "%d", &portNumber)
+
+	servers := map[string]interface{}{
+		"Servers": map[string]interface{}{
+			"1": map[string]interface{}{
+				"Name":                 config.Host,
+				"Group":                "Servers",
+				"Host":                 dbHost,
+				"Port":                 portNumber,
+				"MaintenanceDB":        maintenanceDB,
+				"Username":             config.Username,
+				"ConnectionParameters": params,
+			},
+		},
+	}
+	data, err := json.MarshalIndent(servers, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return writeTempFile("containdb-pgadmin-servers-*.json", string(data))
+}
@@ -4,8 +4,6 @@ import (
 	"ContainDB/src/Docker"
 	"fmt"
 	"os"
-	"os/exec"
-	"strings"
 
 	"github.com/manifoldco/promptui"
 )
@@ -16,7 +14,7 @@ func StartRedisInsight() {
 		fmt.Println("RedisInsight is already running.")
 		if Docker.AskYesNo("Do you want to remove the existing RedisInsight container and create a new one?") {
 			fmt.Println("Removing existing RedisInsight container...")
-			cmd := exec.Command("docker", "rm", "-f", "redisinsight")
+			cmd := Docker.Command("rm", "-f", "redisinsight")
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
 			if err := cmd.Run(); err != nil {
@@ -30,16 +28,31 @@ func StartRedisInsight() {
 		}
 	}
 
-	// Look for running Redis containers
-	redisContainers := Docker.ListOfContainers([]string{"redis"})
-	if len(redisContainers) == 0 {
-		fmt.Println("No running Redis containers found.")
+	// Look for running Redis-compatible containers (Redis, Valkey, KeyDB)
+	var redisContainers []string
+	for _, name := range Docker.ListOfContainers([]string{"redis", "valkey", "keydb"}) {
+		if name != "redisinsight" {
+			redisContainers = append(redisContainers, name)
+		}
+	}
+
+	connectionType := selectConnectionType("RedisInsight", len(redisContainers) > 0)
+	if connectionType == "exit" {
+		fmt.Println("Exiting RedisInsight setup.")
 		return
 	}
+	if connectionType == "local" {
+		startRedisInsightLocal(redisContainers)
+	} else {
+		startRedisInsightRemote()
+	}
+}
 
+// startRedisInsightLocal links RedisInsight to a Redis-compatible container on ContainDB-Network
+func startRedisInsightLocal(redisContainers []string) {
 	items := append(redisContainers, "Exit")
 	prompt := promptui.Select{
-		Label: "Select a Redis container to link with RedisInsight",
+		Label: "Select a Redis-compatible container to link with RedisInsight",
 		Items: items,
 	}
 	_, selectedContainer, err := prompt.Run()
@@ -52,33 +65,127 @@ func StartRedisInsight() {
 		return
 	}
 
-	port := AskForInput("Enter host port to expose RedisInsight", "8001")
+	bind, port := askToolPort("Enter host port to expose RedisInsight", "8001")
+
+	password := Docker.GetContainerEnv(selectedContainer, "REDIS_PASSWORD")
+	if password != "" {
+		var seed bool
+		if bind, seed = seededPasswordBind(selectedContainer, bind, port); !seed {
+			password = ""
+		}
+	}
 
 	fmt.Printf("Pulling RedisInsight image...\n")
-	cmd := exec.Command("docker", "pull", "redis/redisinsight:latest")
+	cmd := Docker.Command("pull", "redis/redisinsight:latest")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	_ = cmd.Run()
 
-	// Build docker run command as args array
+	// Pre-configure the connection, with the password the container was installed with
 	args := []string{
-		"run", "-d",
 		"--restart", "unless-stopped",
 		"--network", "ContainDB-Network",
-		"--name", "redisinsight",
-		"-p", fmt.Sprintf("%s:5540", port),
-		"redis/redisinsight:latest",
+		"-e", fmt.Sprintf("RI_REDIS_HOST=%s", selectedContainer),
+		"-e", "RI_REDIS_PORT=6379",
+		"-e", fmt.Sprintf("RI_REDIS_ALIAS=%s", selectedContainer),
+	}
+	if password != "" {
+		args = append(args, "-e", fmt.Sprintf("RI_REDIS_PASSWORD=%s", password))
 	}
+	// A TLS instance only listens for TLS on 6379; its certificate names the container
+	files := instanceCAFiles(selectedContainer)
+	if files != nil {
+		args = append(args, "-e", "RI_REDIS_TLS=true", "-e", fmt.Sprintf("RI_REDIS_TLS_CA_PATH=%s", remoteCAPath))
+	}
+	args = append(args, toolPortArgs(bind, port, "5540")...)
+	args = append(args, "redis/redisinsight:latest")
+
+	if err := runWithFiles("redisinsight", args, files); err != nil {
+		fmt.Println("Error starting RedisInsight:", err)
+	} else {
+		fmt.Printf("\n✅ RedisInsight started. Access it at: %s\n", toolURL("redisinsight", bind, port))
+		fmt.Printf("   Connected to %s", selectedContainer)
+		if password != "" {
+			fmt.Print(" with its password")
+		} else if Docker.GetContainerEnv(selectedContainer, "REDIS_PASSWORD") != "" {
+			fmt.Print("; enter its password when RedisInsight asks")
+		}
+		fmt.Println()
+	}
+}
+
+// startRedisInsightRemote pre-configures RedisInsight with a remote Redis endpoint
+// (e.g. ElastiCache, Azure Cache or Redis Cloud)
+func startRedisInsightRemote() {
+	config := getRemoteTarget("6379", false, true)
+	if config.Host == "" {
+		fmt.Println("Error: Redis host cannot be empty")
+		return
+	}
+
+	bind, port := askToolPort("Enter host port to expose RedisInsight", "8001")
 
-	fmt.Println("Running: docker", strings.Join(args, " "))
-	cmd = exec.Command("docker", args...)
+	fmt.Printf("Pulling RedisInsight image...\n")
+	cmd := Docker.Command("pull", "redis/redisinsight:latest")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	_ = cmd.Run()
+
+	dbHost, dbPort, network, err := config.connect("redisinsight")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	args := []string{
+		"--restart", "unless-stopped",
+		"--network", network,
+		"-e", fmt.Sprintf("RI_REDIS_HOST=%s", dbHost),
+		"-e", fmt.Sprintf("RI_REDIS_PORT=%s", dbPort),
+		"-e", fmt.Sprintf("RI_REDIS_ALIAS=%s", config.Host),
+	}
+	if config.Username != "" {
+		args = append(args, "-e", fmt.Sprintf("RI_REDIS_USERNAME=%s", config.Username))
+	}
+	if config.Password != "" {
+		if seedBind, seed := seededPasswordBind(config.Host, bind, port); seed {
+			bind = seedBind
+			args = append(args, "-e", fmt.Sprintf("RI_REDIS_PASSWORD=%s", config.Password))
+		}
+	}
+	if config.EnableSSL {
+		args = append(args, "-e", "RI_REDIS_TLS=true")
+		if config.CAFile != "" {
+			args = append(args, "-e", fmt.Sprintf("RI_REDIS_TLS_CA_PATH=%s", remoteCAPath))
+		}
+	}
+	args = append(args, toolPortArgs(bind, port, "5540")...)
+	args = append(args, "redis/redisinsight:latest")
+
+	if err := runWithFiles("redisinsight", args, config.files()); err != nil {
 		fmt.Println("Error starting RedisInsight:", err)
 	} else {
-		fmt.Printf("\n✅ RedisInsight started. Access it at: http://localhost:%s\n", port)
-		fmt.Printf("👉 In the RedisInsight UI, add a Redis database with host: `%s`, port: `6379`\n", selectedContainer)
-		fmt.Println("   (RedisInsight will resolve container name using Docker network DNS.)")
+		fmt.Printf("\n✅ RedisInsight started. Access it at: %s\n", toolURL("redisinsight", bind, port))
+		fmt.Printf("👉 The database '%s:%s' is pre-configured in RedisInsight.\n", config.Host, config.Port)
+	}
+}
+
+// seededPasswordBind returns the address to publish RedisInsight on when it is
+// pre-configured with the password of target. RedisInsight shows that password
+// to anyone reaching its UI, so the UI is kept on this machine: a port is bound
+// to loopback, and behind a gateway reachable from other machines the password
+// is not injected (false) and entered in the UI instead.
+func seededPasswordBind(target, bind, port string) (string, bool) {
+	if port == "" {
+		if address, _ := gatewayBinding(); !isLoopback(address) {
+			fmt.Printf("⚠️  The gateway is reachable from other machines; enter the password of %s in the RedisInsight UI.\n", target)
+			return bind, false
+		}
+		return bind, true
+	}
+	if !isLoopback(bind) {
+		fmt.Printf("⚠️  RedisInsight is pre-configured with the password of %s; publishing it on %s only.\n", target, LoopbackAddress)
+		return LoopbackAddress, true
 	}
+	return bind, true
 }
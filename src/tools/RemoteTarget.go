@@ -0,0 +1,304 @@
+package tools
+
+import (
+	"ContainDB/src/Docker"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// remoteCAPath is where a tool container finds the CA certificate of a remote
+// target, or the local CA for a ContainDB instance serving TLS
+const remoteCAPath = "/tmp/containdb-ca.pem"
+
+// tunnelKeyPath is where the SSH tunnel container finds the bastion's private key
+const tunnelKeyPath = "/tmp/containdb-ssh-key"
+
+// tunnelImage is alpine with the SSH client, built once so the tunnel starts
+// without network access after a reboot. The localhost/ prefix is where podman
+// commits local images, and keeps the name from being qualified to Docker Hub.
+const tunnelImage = "localhost/containdb-ssh-tunnel:latest"
+
+// RemoteTarget holds the connection parameters of a database outside
+// ContainDB-Network (RDS, ElastiCache, Elastic Cloud, a VM behind a bastion...)
+// that a management tool should connect to.
+type RemoteTarget struct {
+	Host      string
+	Port      string
+	Username  string
+	Password  string
+	Database  string // Optional
+	EnableSSL bool
+	CAFile    string     // Optional CA certificate (PEM) used to verify the server
+	SSHTunnel *SSHTunnel // Optional bastion host to reach the database through
+}
+
+// SSHTunnel describes the bastion host used to reach a RemoteTarget
+type SSHTunnel struct {
+	Host    string
+	Port    string
+	User    string
+	KeyFile string
+}
+
+// selectConnectionType prompts user to choose between local container or remote database
+// for the given management tool
+func selectConnectionType(tool string, hasLocalContainers bool) string {
+	items := []string{}
+
+	if hasLocalContainers {
+		items = append(items, "Local Container")
+	}
+	items = append(items, "Remote / Cloud Database", "Exit")
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Select %s connection type", tool),
+		Items: items,
+	}
+	_, selected, err := prompt.Run()
+	if err != nil {
+		fmt.Println("\n⚠️ Interrupt received, rolling back...")
+		Cleanup()
+		return "exit"
+	}
+
+	if selected == "Exit" {
+		return "exit"
+	} else if selected == "Local Container" {
+		return "local"
+	} else {
+		return "remote"
+	}
+}
+
+// getRemoteTarget collects remote database connection parameters from user.
+// Tools with a login form of their own (Adminer) take the password there, so
+// askPassword is false for them.
+func getRemoteTarget(defaultPort string, askDatabase, askPassword bool) RemoteTarget {
+	fmt.Println("\n🌐 Remote Database Configuration")
+
+	host := AskForInput("Enter database host (e.g., db.example.com or a cloud endpoint)", "")
+	port := AskForInput("Enter database port", defaultPort)
+	username := AskForInput("Enter database username (leave empty if none)", "")
+	password := ""
+	if askPassword {
+		password = AskForInput("Enter database password (leave empty if none)", "")
+	}
+	database := ""
+	if askDatabase {
+		database = AskForInput("Enter database name (optional, leave empty for none)", "")
+	}
+
+	target := RemoteTarget{
+		Host:     strings.TrimSpace(host),
+		Port:     strings.TrimSpace(port),
+		Username: strings.TrimSpace(username),
+		Password: strings.TrimSpace(password),
+		Database: strings.TrimSpace(database),
+	}
+
+	target.EnableSSL = Docker.AskYesNo("Enable SSL/TLS connection?")
+	if target.EnableSSL && Docker.AskYesNo("Verify the server with a CA certificate file (e.g. rds-combined-ca-bundle.pem)?") {
+		target.CAFile = askExistingFile("Enter path to CA certificate (PEM)")
+	}
+
+	if Docker.AskYesNo("Connect through an SSH tunnel (bastion host)?") {
+		target.SSHTunnel = &SSHTunnel{
+			Host:    strings.TrimSpace(AskForInput("Enter SSH bastion host", "")),
+			Port:    strings.TrimSpace(AskForInput("Enter SSH port", "22")),
+			User:    strings.TrimSpace(AskForInput("Enter SSH user", "ec2-user")),
+			KeyFile: askExistingFile("Enter path to SSH private key"),
+		}
+	}
+
+	return target
+}
+
+// askExistingFile prompts until the user enters the path of an existing file
+func askExistingFile(label string) string {
+	for {
+		path := strings.TrimSpace(AskForInput(label, ""))
+		if strings.HasPrefix(path, "~"+string(os.PathSeparator)) {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			absPath, _ := filepath.Abs(path)
+			return absPath
+		}
+		fmt.Printf("File '%s' not found, please try again.\n", path)
+	}
+}
+
+// connect prepares the network path from a tool container to the target and
+// returns the host, port and Docker network the tool should use. Without an SSH
+// tunnel the tool runs on the default bridge network and dials the target
+// directly; with one, a "<tool>-tunnel" container on ContainDB-Network forwards
+// the port through the bastion host and the tool dials that container instead.
+func (t RemoteTarget) connect(tool string) (string, string, string, error) {
+	tunnelName := tool + "-tunnel"
+	// Drop the tunnel of a previous setup of this tool, if any
+	_ = Docker.Command("rm", "-f", tunnelName).Run()
+
+	if t.SSHTunnel == nil {
+		return t.Host, t.Port, "bridge", nil
+	}
+
+	if err := ensureTunnelImage(); err != nil {
+		return "", "", "", fmt.Errorf("failed to prepare the SSH tunnel image: %v", err)
+	}
+
+	fmt.Printf("Starting SSH tunnel via %s@%s...\n", t.SSHTunnel.User, t.SSHTunnel.Host)
+	createArgs := []string{
+		"--restart", "unless-stopped",
+		"--network", "ContainDB-Network",
+		tunnelImage, "ssh", "-N",
+		"-o", "StrictHostKeyChecking=accept-new", "-o", "ServerAliveInterval=30", "-o", "ExitOnForwardFailure=yes",
+		"-i", tunnelKeyPath, "-p", t.SSHTunnel.Port,
+		"-L", fmt.Sprintf("0.0.0.0:%s:%s:%s", t.Port, t.Host, t.Port),
+		fmt.Sprintf("%s@%s", t.SSHTunnel.User, t.SSHTunnel.Host),
+	}
+	files := map[string]string{tunnelKeyPath: t.SSHTunnel.KeyFile}
+	if err := runWithFiles(tunnelName, createArgs, files); err != nil {
+		return "", "", "", fmt.Errorf("failed to start SSH tunnel: %v", err)
+	}
+
+	return tunnelName, t.Port, "ContainDB-Network", nil
+}
+
+// ensureTunnelImage builds tunnelImage once by installing the SSH client in an
+// alpine container and committing it
+func ensureTunnelImage() error {
+	if Docker.Command("image", "inspect", tunnelImage).Run() == nil {
+		return nil
+	}
+	fmt.Println("Building the SSH tunnel image (once)...")
+	const builder = "containdb-ssh-tunnel-build"
+	_ = Docker.Command("rm", "-f", builder).Run()
+	defer Docker.Command("rm", "-f", builder).Run()
+
+	if out, err := Docker.Command("run", "--name", builder, "alpine:latest", "apk", "add", "--no-cache", "openssh-client").CombinedOutput(); err != nil {
+		return fmt.Errorf("%v %s", err, strings.TrimSpace(string(out)))
+	}
+	if out, err := Docker.Command("commit", builder, tunnelImage).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// files returns the files a tool container needs for this target, keyed by their
+// path inside the container
+func (t RemoteTarget) files() map[string]string {
+	files := map[string]string{}
+	if t.CAFile != "" {
+		files[remoteCAPath] = t.CAFile
+	}
+	return files
+}
+
+// instanceCAFiles returns the files a tool container needs to verify a
+// ContainDB instance serving TLS: the local CA at remoteCAPath. nil when the
+// instance serves plaintext.
+func instanceCAFiles(container string) map[string]string {
+	if Docker.GetContainerEnv(container, Docker.TLSCAEnv) == "" {
+		return nil
+	}
+	return map[string]string{remoteCAPath: Docker.CACertPath()}
+}
+
+// scheme returns "https" when TLS is enabled, "http" otherwise
+func (t RemoteTarget) scheme() string {
+	if t.EnableSSL {
+		return "https"
+	}
+	return "http"
+}
+
+// verificationMode returns the TLS verification mode for Kibana-style settings.
+// Through a tunnel the host name no longer matches the certificate, so only
+// the certificate chain can be verified.
+func (t RemoteTarget) verificationMode() string {
+	if t.SSHTunnel != nil {
+		return "certificate"
+	}
+	return "full"
+}
+
+// runWithFiles creates a container, copies host files into it and starts it.
+// files maps the destination path inside the container to the host source path.
+// createArgs are the `docker create` flags followed by the image and its command.
+func runWithFiles(name string, createArgs []string, files map[string]string) error {
+	args := append([]string{"create", "--name", name}, createArgs...)
+	fmt.Println("Running:", Docker.RuntimeName(), strings.Join(maskedArgs(args), " "))
+	cmd := Docker.Command(args...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	// Copied with explicit modes: host temp files are private, but tools running
+	// as their own user must read them. ssh only accepts a private key.
+	var copies []Docker.ContainerFile
+	for dest, src := range files {
+		content, err := os.ReadFile(src)
+		if err != nil {
+			_ = Docker.Command("rm", "-f", name).Run()
+			return fmt.Errorf("failed to read %s: %v", src, err)
+		}
+		mode := int64(0644)
+		if dest == tunnelKeyPath {
+			mode = 0600
+		}
+		copies = append(copies, Docker.ContainerFile{Path: dest, Content: content, Mode: mode})
+	}
+	if len(copies) > 0 {
+		if err := Docker.CopyFilesToContainer(name, copies); err != nil {
+			_ = Docker.Command("rm", "-f", name).Run()
+			return err
+		}
+	}
+
+	cmd = Docker.Command("start", name)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// maskedArgs hides the passwords and credential URLs among -e arguments, for printing
+func maskedArgs(args []string) []string {
+	masked := make([]string, len(args))
+	for i, arg := range args {
+		masked[i] = arg
+		if i == 0 || args[i-1] != "-e" {
+			continue
+		}
+		key, value, _ := strings.Cut(arg, "=")
+		if strings.Contains(key, "PASSWORD") || (strings.Contains(value, "://") && strings.Contains(value, "@")) {
+			masked[i] = key + "=****"
+		}
+	}
+	return masked
+}
+
+// writeTempFile writes generated content (e.g. a tool's server list) to a
+// private temporary file that can be copied into a container with runWithFiles.
+// pattern is passed to os.CreateTemp, e.g. "containdb-servers-*.json".
+func writeTempFile(pattern, content string) (string, error) {
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
@@ -4,7 +4,6 @@ import (
 	"ContainDB/src/Docker"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -16,16 +15,26 @@ func Cleanup() {
 	// remove exited/dead containers - get list first, then remove
 	fmt.Println("- Removing failed containers...")
 
+	// Stopped containers on ContainDB-Network are kept: they may be databases the
+	// user stopped on purpose and wants to start again later
+	stopped := map[string]bool{}
+	cmd := Docker.Command("ps", "-a", "--filter", "network=ContainDB-Network", "--filter", "status=exited", "--format", "{{.ID}}")
+	if output, err := cmd.Output(); err == nil {
+		for _, id := range strings.Fields(string(output)) {
+			stopped[id] = true
+		}
+	}
+
 	statuses := []string{"exited", "dead", "created"}
 	for _, status := range statuses {
 		// Get containers with the specific status
-		cmd := exec.Command("docker", "ps", "-a", "--filter", fmt.Sprintf("status=%s", status), "--format", "{{.ID}}")
+		cmd := Docker.Command("ps", "-a", "--filter", fmt.Sprintf("status=%s", status), "--format", "{{.ID}}")
 		output, err := cmd.Output()
 		if err == nil {
 			containerIDs := strings.Fields(strings.TrimSpace(string(output)))
 			for _, id := range containerIDs {
-				if id != "" {
-					rmCmd := exec.Command("docker", "rm", "-f", id)
+				if id != "" && !stopped[id] {
+					rmCmd := Docker.Command("rm", "-f", id)
 					rmCmd.Run()
 				}
 			}
@@ -34,7 +43,7 @@ func Cleanup() {
 
 	// remove dangling images
 	fmt.Println("- Removing dangling images...")
-	exec.Command("docker", "image", "prune", "-f").Run()
+	Docker.Command("image", "prune", "-f").Run()
 
 	// clean up MongoDB Compass download - use cross-platform temp dir
 	tempDir := Docker.GetTempDir()